@@ -0,0 +1,321 @@
+package gospec
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"testing/fstest"
+)
+
+// creators of zip entries with unix permissions, see the APPNOTE.TXT of zip.
+const (
+	zipCreatorUnix   = 3
+	zipCreatorMacOSX = 19
+)
+
+// archiveOf returns the content of archive, which is a path, a []byte or an io.Reader.
+func archiveOf(archive interface{}) ([]byte, error) {
+	switch v := archive.(type) {
+	case string:
+		return os.ReadFile(v)
+
+	case []byte:
+		return v, nil
+
+	case io.Reader:
+		return io.ReadAll(v)
+
+	}
+
+	return nil, fmt.Errorf("archive must be a path, a []byte or an io.Reader, but got %T", archive)
+}
+
+// openArchive returns the tree of a zip, tar or tar.gz archive, which is detected by magic numbers.
+func openArchive(archive interface{}) (fs.FS, error) {
+	data, err := archiveOf(archive)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")), bytes.HasPrefix(data, []byte("PK\x05\x06")):
+		return zipTree(data)
+
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+
+		return tarTree(gz)
+
+	case len(data) > 262 && string(data[257:262]) == "ustar":
+		return tarTree(bytes.NewReader(data))
+
+	}
+
+	return nil, fmt.Errorf("archive must be zip, tar or tar.gz")
+}
+
+// zipTree reads all entries of the zip archive into a tree. Permissions of entries are dropped
+// unless the archive is created on unix, as they're made up by archive/zip otherwise.
+func zipTree(data []byte) (fs.FS, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	tree := fstest.MapFS{}
+	for _, file := range zr.File {
+		name := path.Clean(strings.TrimPrefix(file.Name, "./"))
+		if name == "." {
+			continue
+		}
+
+		mode := file.Mode()
+		if creator := file.CreatorVersion >> 8; creator != zipCreatorUnix && creator != zipCreatorMacOSX {
+			mode &^= fs.ModePerm
+		}
+
+		entry := &fstest.MapFile{
+			Mode:    mode,
+			ModTime: file.Modified,
+		}
+
+		if !mode.IsDir() {
+			r, err := file.Open()
+			if err != nil {
+				return nil, err
+			}
+
+			entry.Data, err = io.ReadAll(r)
+			r.Close()
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		tree[name] = entry
+	}
+
+	return tree, nil
+}
+
+// tarTree reads all entries of the tar stream into a tree. Hard links are read as their targets,
+// and entries other than files, directories and symlinks, e.g. devices, are not supported.
+func tarTree(r io.Reader) (fs.FS, error) {
+	tree := fstest.MapFS{}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return tree, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+		if name == "." {
+			continue
+		}
+
+		mode := fs.FileMode(header.Mode).Perm()
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			tree[name] = &fstest.MapFile{
+				Mode:    fs.ModeDir | mode,
+				ModTime: header.ModTime,
+			}
+
+		case tar.TypeSymlink:
+			tree[name] = &fstest.MapFile{
+				Data:    []byte(header.Linkname),
+				Mode:    fs.ModeSymlink | mode,
+				ModTime: header.ModTime,
+			}
+
+		case tar.TypeReg, tar.TypeRegA, tar.TypeGNUSparse:
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, err
+			}
+
+			tree[name] = &fstest.MapFile{
+				Data:    data,
+				Mode:    mode,
+				ModTime: header.ModTime,
+			}
+
+		case tar.TypeLink:
+			// hard links refer to files archived before them
+			target, ok := tree[path.Clean(strings.TrimPrefix(header.Linkname, "./"))]
+			if !ok || !target.Mode.IsRegular() {
+				return nil, fmt.Errorf("hard link %s refers to missing file %s", header.Name, header.Linkname)
+			}
+
+			tree[name] = &fstest.MapFile{
+				Data:    target.Data,
+				Mode:    target.Mode,
+				ModTime: header.ModTime,
+			}
+
+		case tar.TypeXGlobalHeader:
+			// ignore
+
+		default:
+			return nil, fmt.Errorf("entry %s of type %q is not supported", header.Name, header.Typeflag)
+
+		}
+	}
+}
+
+// assertArchive opens the archive, reporting a failure if it's impossible.
+func assertArchive(t TestingT, archive interface{}, extras ...interface{}) (fs.FS, bool) {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	tree, err := openArchive(archive)
+	if err != nil {
+		return nil, Errorf(t, fmt.Sprintf("Expect to open archive, but failed: %v", err), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+		}, arguments{
+			"archive": archive,
+		})
+	}
+
+	return tree, true
+}
+
+// ArchiveContains asserts that the specified zip, tar or tar.gz archive has all entries, which are
+// slash-separated paths of files or directories. The archive is a path, a []byte or an io.Reader.
+//
+//	assert.ArchiveContains(t, "dist/app.tar.gz", []string{"bin/app", "README.md"})
+//
+// Returns whether the assertion was successful (true) or not (false).
+func ArchiveContains(t TestingT, archive interface{}, entries []string, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	tree, ok := assertArchive(t, archive, extras...)
+	if !ok {
+		return false
+	}
+
+	var missing []string
+	for _, entry := range entries {
+		if _, err := fs.Stat(tree, path.Clean(strings.TrimPrefix(entry, "./"))); err != nil {
+			missing = append(missing, entry)
+		}
+	}
+
+	if len(missing) > 0 {
+		actentries, _ := walkTree(tree)
+
+		paths := make([]string, 0, len(actentries))
+		for path := range actentries {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		return Errorf(t, fmt.Sprintf("Expect to have all entries, but %d missing", len(missing)), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-missing",
+				content: strings.Join(missing, "\n"),
+			},
+			{
+				label:   "+entries",
+				content: strings.Join(paths, "\n"),
+			},
+		}, arguments{
+			"archive": archive,
+			"entries": entries,
+		})
+	}
+
+	return true
+}
+
+// ArchiveEqualsDir asserts that the specified zip, tar or tar.gz archive has the same files and directories
+// as expected, by names, modes and contents, which is reported like DirTreeEqual.
+//
+//	assert.ArchiveEqualsDir(t, "dist/app.zip", os.DirFS("testdata/app"))
+//
+// Returns whether the assertion was successful (true) or not (false).
+func ArchiveEqualsDir(t TestingT, archive interface{}, expected fs.FS, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	tree, ok := assertArchive(t, archive, extras...)
+	if !ok {
+		return false
+	}
+
+	return assertTreeEqual(t, expected, tree, arguments{
+		"archive":  archive,
+		"expected": expected,
+	}, extras...)
+}
+
+// ArchiveEntryContent asserts that the entry of the specified zip, tar or tar.gz archive has the content,
+// which is a string or []byte.
+//
+//	assert.ArchiveEntryContent(t, "dist/app.zip", "VERSION", "1.2.3\n")
+//
+// Returns whether the assertion was successful (true) or not (false).
+func ArchiveEntryContent(t TestingT, archive interface{}, name string, content interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	tree, ok := assertArchive(t, archive, extras...)
+	if !ok {
+		return false
+	}
+
+	labels := []labeledOutput{
+		{
+			label:   "-entry",
+			content: name,
+		},
+	}
+
+	file, err := tree.Open(path.Clean(strings.TrimPrefix(name, "./")))
+	if err != nil {
+		return Errorf(t, fmt.Sprintf("Expect to open archive entry, but failed: %v", err), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+		}, labels, arguments{
+			"archive": archive,
+			"name":    name,
+			"content": content,
+		})
+	}
+	defer file.Close()
+
+	return assertEqualStreams(t, bytes.NewReader(needleOf(content)), file, labels, extras...)
+}
@@ -0,0 +1,162 @@
+package gospec
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func testingZip(t *testing.T) []byte {
+	buf := new(bytes.Buffer)
+
+	zw := zip.NewWriter(buf)
+
+	w, _ := zw.Create("hello.txt")
+	w.Write([]byte("Hello, world!\n"))
+
+	header := &zip.FileHeader{Name: "sub/tool.sh"}
+	header.SetMode(0755)
+
+	w, _ = zw.CreateHeader(header)
+	w.Write([]byte("#!/bin/sh\necho ok\n"))
+
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func testingTarGz(t *testing.T) []byte {
+	buf := new(bytes.Buffer)
+
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+
+	tw.WriteHeader(&tar.Header{Name: "./sub/", Typeflag: tar.TypeDir, Mode: 0755})
+	for _, file := range []struct {
+		name, content string
+		mode          int64
+	}{
+		{"./hello.txt", "Hello, world!\n", 0644},
+		{"./sub/tool.sh", "#!/bin/sh\necho ok\n", 0755},
+	} {
+		tw.WriteHeader(&tar.Header{Name: file.name, Typeflag: tar.TypeReg, Mode: file.mode, Size: int64(len(file.content))})
+		tw.Write([]byte(file.content))
+	}
+
+	tw.Close()
+	gz.Close()
+
+	return buf.Bytes()
+}
+
+func TestArchiveContains(t *testing.T) {
+	mockT := new(testing.T)
+
+	for _, archive := range [][]byte{testingZip(t), testingTarGz(t)} {
+		True(t, ArchiveContains(mockT, archive, []string{"hello.txt", "sub", "./sub/tool.sh"}))
+		True(t, ArchiveContains(mockT, bytes.NewReader(archive), []string{"sub/"}))
+		False(t, ArchiveContains(mockT, archive, []string{"hello.txt", "missing.txt"}))
+	}
+
+	False(t, ArchiveContains(mockT, []byte("not an archive"), []string{"hello.txt"}))
+	False(t, ArchiveContains(mockT, 1, []string{"hello.txt"}))
+	False(t, ArchiveContains(mockT, "testdata/missing.zip", []string{"hello.txt"}))
+}
+
+func TestArchiveEqualsDir(t *testing.T) {
+	mockT := new(testing.T)
+
+	dir := testingDir(t)
+	defer os.RemoveAll(dir)
+
+	zipfile := filepath.Join(dir, "..", filepath.Base(dir)+".zip")
+	os.WriteFile(zipfile, testingZip(t), 0644)
+	defer os.Remove(zipfile)
+
+	// permissions of zip entries without unix modes are ignored
+	True(t, ArchiveEqualsDir(mockT, zipfile, os.DirFS(dir)))
+	True(t, ArchiveEqualsDir(mockT, testingTarGz(t), os.DirFS(dir)))
+
+	expected := fstest.MapFS{
+		"hello.txt":   {Data: []byte("Hello, gopher!\n")},
+		"sub/tool.sh": {Data: []byte("#!/bin/sh\necho ok\n"), Mode: 0700},
+	}
+
+	rt := new(RecordingT)
+
+	False(t, ArchiveEqualsDir(rt, testingTarGz(t), expected))
+
+	records := rt.Records()
+	if Len(t, records, 1) {
+		Equal(t, "Expect trees to be equal, but 0 missing, 0 added and 2 changed", records[0].Error)
+		Contains(t, testingLabelOf(records[0], "Changed"), "hello.txt: differ at byte 7 (line 1, column 8)")
+		Contains(t, testingLabelOf(records[0], "Changed"), "sub/tool.sh: mode -rwx------ != -rwxr-xr-x")
+	}
+
+	// arguments are described by params of ArchiveEqualsDir
+	rt.Reset()
+
+	False(t, ArchiveEqualsDir(rt, zipfile, expected))
+	if records := rt.Records(); Len(t, records, 1) {
+		expression := testingLabelOf(records[0], labelExpression)
+		Contains(t, expression, fmt.Sprintf("zipfile → %q", zipfile))
+		NotContains(t, expression, "zipfile → fstest.MapFS")
+	}
+}
+
+func TestArchiveEntryContent(t *testing.T) {
+	mockT := new(testing.T)
+
+	for _, archive := range [][]byte{testingZip(t), testingTarGz(t)} {
+		True(t, ArchiveEntryContent(mockT, archive, "hello.txt", "Hello, world!\n"))
+		True(t, ArchiveEntryContent(mockT, archive, "./sub/tool.sh", []byte("#!/bin/sh\necho ok\n")))
+		False(t, ArchiveEntryContent(mockT, archive, "hello.txt", "Hello, world!"))
+		False(t, ArchiveEntryContent(mockT, archive, "missing.txt", ""))
+	}
+}
+
+func Test_tarTree(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	tw := tar.NewWriter(buf)
+	tw.WriteHeader(&tar.Header{Name: "./hello.txt", Typeflag: tar.TypeReg, Mode: 0644, Size: 14})
+	tw.Write([]byte("Hello, world!\n"))
+	tw.WriteHeader(&tar.Header{Name: "./link.txt", Typeflag: tar.TypeLink, Linkname: "./hello.txt"})
+	tw.Close()
+
+	tree, err := tarTree(bytes.NewReader(buf.Bytes()))
+	if Nil(t, err) {
+		data, err := fs.ReadFile(tree, "link.txt")
+		Nil(t, err)
+		Equal(t, "Hello, world!\n", string(data))
+	}
+
+	// hard link to missing file
+	buf.Reset()
+
+	tw = tar.NewWriter(buf)
+	tw.WriteHeader(&tar.Header{Name: "./link.txt", Typeflag: tar.TypeLink, Linkname: "./hello.txt"})
+	tw.Close()
+
+	_, err = tarTree(bytes.NewReader(buf.Bytes()))
+	EqualErrors(t, err, "hard link ./link.txt refers to missing file ./hello.txt")
+
+	// unsupported entry
+	buf.Reset()
+
+	tw = tar.NewWriter(buf)
+	tw.WriteHeader(&tar.Header{Name: "./pipe", Typeflag: tar.TypeFifo, Mode: 0644})
+	tw.Close()
+
+	_, err = tarTree(bytes.NewReader(buf.Bytes()))
+	EqualErrors(t, err, `entry ./pipe of type '6' is not supported`)
+}
@@ -1,9 +1,11 @@
 package gospec
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"math"
+	"math/big"
 	"reflect"
 	"strconv"
 	"strings"
@@ -18,8 +20,12 @@ import (
 //
 // Returns whether the assertion was successful (true) or not (false).
 func IsType(t TestingT, expected, actual interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	if !DeepEqual(reflect.TypeOf(expected), reflect.TypeOf(actual)) {
-		exps, acts := toString(expected, actual)
+		exps, acts := configOf(t).toString(expected, actual)
 
 		return Errorf(t, "Expect to be of the same type", []labeledOutput{
 			{
@@ -34,6 +40,9 @@ func IsType(t TestingT, expected, actual interface{}, extras ...interface{}) boo
 				label:   "-received",
 				content: acts,
 			},
+		}, arguments{
+			"expected": expected,
+			"actual":   actual,
 		})
 	}
 
@@ -46,8 +55,12 @@ func IsType(t TestingT, expected, actual interface{}, extras ...interface{}) boo
 //
 // Returns whether the assertion was successful (true) or not (false).
 func Implements(t TestingT, expectedIface, actual interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	if expectedIface == nil || actual == nil {
-		iface, value := toString(expectedIface, actual)
+		iface, value := configOf(t).toString(expectedIface, actual)
 
 		return Errorf(t, "Expect to implement interface", []labeledOutput{
 			{
@@ -62,12 +75,15 @@ func Implements(t TestingT, expectedIface, actual interface{}, extras ...interfa
 				label:   "+value",
 				content: value,
 			},
+		}, arguments{
+			"expectedIface": expectedIface,
+			"actual":        actual,
 		})
 	}
 
 	ifaceType := reflect.TypeOf(expectedIface).Elem()
 	if !reflect.TypeOf(actual).Implements(ifaceType) {
-		iface, value := toString(expectedIface, actual)
+		iface, value := configOf(t).toString(expectedIface, actual)
 
 		return Errorf(t, "Expect to implement interface", []labeledOutput{
 			{
@@ -82,6 +98,9 @@ func Implements(t TestingT, expectedIface, actual interface{}, extras ...interfa
 				label:   "+value",
 				content: value,
 			},
+		}, arguments{
+			"expectedIface": expectedIface,
+			"actual":        actual,
 		})
 	}
 
@@ -97,6 +116,10 @@ func Implements(t TestingT, expectedIface, actual interface{}, extras ...interfa
 // Pointer variable equality is determined based on the equality of the
 // referenced values (as opposed to the memory addresses).
 func Equal(t TestingT, expected, actual interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	if !DeepEqual(expected, actual) {
 		return Errorf(t, "Expect to be equal", []labeledOutput{
 			{
@@ -104,9 +127,12 @@ func Equal(t TestingT, expected, actual interface{}, extras ...interface{}) bool
 				content: formatExtras(extras...),
 			},
 			{
-				label:   "Diff",
-				content: diff(expected, actual),
+				label:   labelDiff,
+				content: configOf(t).diff(expected, actual),
 			},
+		}, arguments{
+			"expected": expected,
+			"actual":   actual,
 		})
 	}
 
@@ -122,8 +148,12 @@ func Equal(t TestingT, expected, actual interface{}, extras ...interface{}) bool
 // Pointer variable equality is determined based on the equality of the
 // referenced values (as opposed to the memory addresses).
 func NotEqual(t TestingT, expected, actual interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	if DeepEqual(expected, actual) {
-		exps, acts := toString(expected, actual)
+		exps, acts := configOf(t).toString(expected, actual)
 
 		return Errorf(t, "Expect to be NOT equal", []labeledOutput{
 			{
@@ -138,6 +168,9 @@ func NotEqual(t TestingT, expected, actual interface{}, extras ...interface{}) b
 				label:   "+received",
 				content: acts,
 			},
+		}, arguments{
+			"expected": expected,
+			"actual":   actual,
 		})
 	}
 
@@ -151,6 +184,10 @@ func NotEqual(t TestingT, expected, actual interface{}, extras ...interface{}) b
 //
 // Returns whether the assertion was successful (true) or not (false).
 func EqualValues(t TestingT, expected, actual interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	if !DeepEqualValues(expected, actual) {
 		return Errorf(t, "Expect to be equal in values", []labeledOutput{
 			{
@@ -158,9 +195,41 @@ func EqualValues(t TestingT, expected, actual interface{}, extras ...interface{}
 				content: formatExtras(extras...),
 			},
 			{
-				label:   "Diff",
-				content: diff(expected, actual),
+				label:   labelDiff,
+				content: configOf(t).diff(expected, actual),
+			},
+		}, arguments{
+			"expected": expected,
+			"actual":   actual,
+		})
+	}
+
+	return true
+}
+
+// EqualBytes asserts that two byte slices are equal, and reports differences as a hex dump diff.
+//
+//    assert.EqualBytes(t, []byte{0x01, 0x02}, frame.Bytes(), "frame should be encoded as 0x0102")
+//
+// Returns whether the assertion was successful (true) or not (false).
+func EqualBytes(t TestingT, expected, actual []byte, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	if !bytes.Equal(expected, actual) {
+		return Errorf(t, "Expect to be equal in bytes", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   labelDiff,
+				content: diffBytes(expected, actual, configOf(t).HexWindow),
 			},
+		}, arguments{
+			"expected": expected,
+			"actual":   actual,
 		})
 	}
 
@@ -173,10 +242,14 @@ func EqualValues(t TestingT, expected, actual interface{}, extras ...interface{}
 //
 // Returns whether the assertion was successful (true) or not (false).
 func EqualJSON(t TestingT, expected, actual string, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	var expectedValue, actualValue interface{}
 
 	if err := json.Unmarshal([]byte(expected), &expectedValue); err != nil {
-		exps, _ := toString(expected, nil)
+		exps, _ := configOf(t).toString(expected, nil)
 
 		return Errorf(t, "Expect value should be valid json.", []labeledOutput{
 			{
@@ -191,11 +264,14 @@ func EqualJSON(t TestingT, expected, actual string, extras ...interface{}) bool
 				label:   "+JSON Parse:",
 				content: err.Error(),
 			},
+		}, arguments{
+			"expected": expected,
+			"actual":   actual,
 		})
 	}
 
 	if err := json.Unmarshal([]byte(actual), &actualValue); err != nil {
-		_, acts := toString(nil, actual)
+		_, acts := configOf(t).toString(nil, actual)
 
 		return Errorf(t, "Actual value should be valid json.", []labeledOutput{
 			{
@@ -210,6 +286,9 @@ func EqualJSON(t TestingT, expected, actual string, extras ...interface{}) bool
 				label:   "+JSON Parse:",
 				content: err.Error(),
 			},
+		}, arguments{
+			"expected": expected,
+			"actual":   actual,
 		})
 	}
 
@@ -222,6 +301,10 @@ func EqualJSON(t TestingT, expected, actual string, extras ...interface{}) bool
 //
 // Returns whether the assertion was successful (true) or not (false).
 func Exactly(t TestingT, expected, actual interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	if !reflect.DeepEqual(expected, actual) {
 		return Errorf(t, "Expect to be equal in deep, both types and values", []labeledOutput{
 			{
@@ -229,9 +312,12 @@ func Exactly(t TestingT, expected, actual interface{}, extras ...interface{}) bo
 				content: formatExtras(extras...),
 			},
 			{
-				label:   "Diff",
-				content: diff(expected, actual),
+				label:   labelDiff,
+				content: configOf(t).diff(expected, actual),
 			},
+		}, arguments{
+			"expected": expected,
+			"actual":   actual,
 		})
 	}
 
@@ -244,6 +330,10 @@ func Exactly(t TestingT, expected, actual interface{}, extras ...interface{}) bo
 //
 // Returns whether the assertion was successful (true) or not (false).
 func Nil(t TestingT, v interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	if !IsNil(v) {
 		var nilval interface{}
 
@@ -255,7 +345,7 @@ func Nil(t TestingT, v interface{}, extras ...interface{}) bool {
 			}
 		}
 
-		exps, acts := toString(nilval, v)
+		exps, acts := configOf(t).toString(nilval, v)
 
 		return Errorf(t, "Expect to be nil", []labeledOutput{
 			{
@@ -270,6 +360,8 @@ func Nil(t TestingT, v interface{}, extras ...interface{}) bool {
 				label:   "+received",
 				content: acts,
 			},
+		}, arguments{
+			"v": v,
 		})
 	}
 
@@ -282,6 +374,10 @@ func Nil(t TestingT, v interface{}, extras ...interface{}) bool {
 //
 // Returns whether the assertion was successful (true) or not (false).
 func NotNil(t TestingT, v interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	if IsNil(v) {
 		var nilval interface{}
 
@@ -293,7 +389,7 @@ func NotNil(t TestingT, v interface{}, extras ...interface{}) bool {
 			}
 		}
 
-		exps, acts := toString(nilval, v)
+		exps, acts := configOf(t).toString(nilval, v)
 
 		return Errorf(t, "Expect to be NOT nil", []labeledOutput{
 			{
@@ -308,6 +404,8 @@ func NotNil(t TestingT, v interface{}, extras ...interface{}) bool {
 				label:   "+received",
 				content: acts,
 			},
+		}, arguments{
+			"v": v,
 		})
 	}
 
@@ -320,9 +418,13 @@ func NotNil(t TestingT, v interface{}, extras ...interface{}) bool {
 //
 // Returns whether the assertion was successful (true) or not (false).
 func True(t TestingT, v interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	val, ok := v.(bool)
 	if !ok || val != true {
-		exps, acts := toString(true, v)
+		exps, acts := configOf(t).toString(true, v)
 
 		return Errorf(t, "Expect to be true", []labeledOutput{
 			{
@@ -337,6 +439,8 @@ func True(t TestingT, v interface{}, extras ...interface{}) bool {
 				label:   "+received",
 				content: acts,
 			},
+		}, arguments{
+			"v": v,
 		})
 	}
 
@@ -349,9 +453,13 @@ func True(t TestingT, v interface{}, extras ...interface{}) bool {
 //
 // Returns whether the assertion was successful (true) or not (false).
 func False(t TestingT, v interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	val, ok := v.(bool)
 	if !ok || val != false {
-		exps, acts := toString(false, v)
+		exps, acts := configOf(t).toString(false, v)
 
 		return Errorf(t, "Expect to be false", []labeledOutput{
 			{
@@ -366,6 +474,8 @@ func False(t TestingT, v interface{}, extras ...interface{}) bool {
 				label:   "+received",
 				content: acts,
 			},
+		}, arguments{
+			"v": v,
 		})
 	}
 
@@ -374,8 +484,12 @@ func False(t TestingT, v interface{}, extras ...interface{}) bool {
 
 // Zero asserts that v is the zero value for its type and returns the truth.
 func Zero(t TestingT, v interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	if v != nil && !reflect.DeepEqual(v, reflect.Zero(reflect.TypeOf(v)).Interface()) {
-		exps, acts := toString(reflect.Zero(reflect.TypeOf(v)).Interface(), v)
+		exps, acts := configOf(t).toString(reflect.Zero(reflect.TypeOf(v)).Interface(), v)
 
 		return Errorf(t, "Expect to be zero", []labeledOutput{
 			{
@@ -390,6 +504,8 @@ func Zero(t TestingT, v interface{}, extras ...interface{}) bool {
 				label:   "+received",
 				content: acts,
 			},
+		}, arguments{
+			"v": v,
 		})
 	}
 
@@ -398,10 +514,14 @@ func Zero(t TestingT, v interface{}, extras ...interface{}) bool {
 
 // NotZero asserts that v is not the zero value for its type and returns the truth.
 func NotZero(t TestingT, v interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	if v == nil || reflect.DeepEqual(v, reflect.Zero(reflect.TypeOf(v)).Interface()) {
 		var acts = "<nil>"
 		if v != nil {
-			_, acts = toString(reflect.Zero(reflect.TypeOf(v)).Interface(), v)
+			_, acts = configOf(t).toString(reflect.Zero(reflect.TypeOf(v)).Interface(), v)
 		}
 
 		return Errorf(t, "Expect to be NOT zero", []labeledOutput{
@@ -417,6 +537,8 @@ func NotZero(t TestingT, v interface{}, extras ...interface{}) bool {
 				label:   "+received",
 				content: acts,
 			},
+		}, arguments{
+			"v": v,
 		})
 	}
 
@@ -430,10 +552,14 @@ func NotZero(t TestingT, v interface{}, extras ...interface{}) bool {
 //
 // Returns whether the assertion was successful (true) or not (false).
 func Empty(t TestingT, v interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	if !IsEmpty(v) {
 		var acts = "<nil>"
 		if v != nil {
-			_, acts = toString(reflect.Zero(reflect.TypeOf(v)).Interface(), v)
+			_, acts = configOf(t).toString(reflect.Zero(reflect.TypeOf(v)).Interface(), v)
 		}
 
 		return Errorf(t, "Expect to be empty", []labeledOutput{
@@ -449,6 +575,8 @@ func Empty(t TestingT, v interface{}, extras ...interface{}) bool {
 				label:   "+received",
 				content: acts,
 			},
+		}, arguments{
+			"v": v,
 		})
 	}
 
@@ -462,10 +590,14 @@ func Empty(t TestingT, v interface{}, extras ...interface{}) bool {
 //
 // Returns whether the assertion was successful (true) or not (false).
 func NotEmpty(t TestingT, v interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	if IsEmpty(v) {
 		var acts = "<nil>"
 		if v != nil {
-			_, acts = toString(reflect.Zero(reflect.TypeOf(v)).Interface(), v)
+			_, acts = configOf(t).toString(reflect.Zero(reflect.TypeOf(v)).Interface(), v)
 		}
 
 		return Errorf(t, "Expect to be NOT empty", []labeledOutput{
@@ -481,6 +613,8 @@ func NotEmpty(t TestingT, v interface{}, extras ...interface{}) bool {
 				label:   "+received",
 				content: acts,
 			},
+		}, arguments{
+			"v": v,
 		})
 	}
 
@@ -494,18 +628,36 @@ func NotEmpty(t TestingT, v interface{}, extras ...interface{}) bool {
 //    assert.Contains(t, ["Hello", "World"], "World", "But ["Hello", "World"] does contain 'World'")
 //    assert.Contains(t, {"Hello": "World"}, "Hello", "But {'Hello': 'World'} does contain 'Hello'")
 //
+// NOTE: io.Reader values neither exposing Bytes() nor seekable are never consumed, but reported as
+// failures, use ReaderContains for them instead.
+//
 // Returns whether the assertion was successful (true) or not (false).
 func Contains(t TestingT, v, element interface{}, extras ...interface{}) bool {
-	if !ContainsElement(v, element) {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	contained, ok := containsOf(t, v, element, extras, arguments{
+		"v":       v,
+		"element": element,
+	})
+	if !ok {
+		return false
+	}
+
+	if !contained {
 		return Errorf(t, "Expect to include substring or element", []labeledOutput{
 			{
 				label:   labelMessages,
 				content: formatExtras(extras...),
 			},
 			{
-				label:   "Diff",
-				content: diff(v, element),
+				label:   labelDiff,
+				content: configOf(t).diff(v, element),
 			},
+		}, arguments{
+			"v":       v,
+			"element": element,
 		})
 	}
 
@@ -519,10 +671,25 @@ func Contains(t TestingT, v, element interface{}, extras ...interface{}) bool {
 //    assert.NotContains(t, ["Hello", "World"], "Earth", "But ['Hello', 'World'] does NOT contain 'Earth'")
 //    assert.NotContains(t, {"Hello": "World"}, "Earth", "But {'Hello': 'World'} does NOT contain 'Earth'")
 //
+// NOTE: io.Reader values neither exposing Bytes() nor seekable are never consumed, but reported as
+// failures, use ReaderContains for them instead.
+//
 // Returns whether the assertion was successful (true) or not (false).
 func NotContains(t TestingT, v, element interface{}, extras ...interface{}) bool {
-	if ContainsElement(v, element) {
-		exps, acts := toString(v, element)
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	contained, ok := containsOf(t, v, element, extras, arguments{
+		"v":       v,
+		"element": element,
+	})
+	if !ok {
+		return false
+	}
+
+	if contained {
+		exps, acts := configOf(t).toString(v, element)
 
 		return Errorf(t, "Expect to NOT include substring or element", []labeledOutput{
 			{
@@ -537,6 +704,9 @@ func NotContains(t TestingT, v, element interface{}, extras ...interface{}) bool
 				label:   "+element",
 				content: acts,
 			},
+		}, arguments{
+			"v":       v,
+			"element": element,
 		})
 	}
 
@@ -550,9 +720,13 @@ func NotContains(t TestingT, v, element interface{}, extras ...interface{}) bool
 //
 // Returns whether the assertion was successful (true) or not (false).
 func Match(t TestingT, r, v interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	reg, ok := tryMatch(r, v)
 	if !ok {
-		_, acts := toString(nil, v)
+		_, acts := configOf(t).toString(nil, v)
 
 		Errorf(t, "Expect to match regexp", []labeledOutput{
 			{
@@ -567,6 +741,9 @@ func Match(t TestingT, r, v interface{}, extras ...interface{}) bool {
 				label:   "+value",
 				content: fmt.Sprintf("%#v", acts),
 			},
+		}, arguments{
+			"r": r,
+			"v": v,
 		})
 	}
 
@@ -580,9 +757,13 @@ func Match(t TestingT, r, v interface{}, extras ...interface{}) bool {
 //
 // Returns whether the assertion was successful (true) or not (false).
 func NotMatch(t TestingT, r, v interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	reg, ok := tryMatch(r, v)
 	if ok {
-		_, acts := toString(nil, v)
+		_, acts := configOf(t).toString(nil, v)
 
 		Errorf(t, "Expect to NOT match regexp", []labeledOutput{
 			{
@@ -597,6 +778,9 @@ func NotMatch(t TestingT, r, v interface{}, extras ...interface{}) bool {
 				label:   "+value",
 				content: fmt.Sprintf("%#v", acts),
 			},
+		}, arguments{
+			"r": r,
+			"v": v,
 		})
 	}
 
@@ -605,9 +789,13 @@ func NotMatch(t TestingT, r, v interface{}, extras ...interface{}) bool {
 
 // Condition uses custom Comparison to assert a complex condition.
 func Condition(t TestingT, comp Comparison, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	ok := comp()
 	if !ok {
-		exps, acts := toString(true, ok)
+		exps, acts := configOf(t).toString(true, ok)
 
 		return Errorf(t, "Expect to return true", []labeledOutput{
 			{
@@ -635,20 +823,27 @@ func Condition(t TestingT, comp Comparison, extras ...interface{}) bool {
 //
 // Returns whether the assertion was successful (true) or not (false).
 func Len(t TestingT, v interface{}, length int, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	n, ok := tryLen(v)
 	if !ok {
-		_, acts := toString(nil, v)
+		_, acts := configOf(t).toString(nil, v)
 
 		return Errorf(t, fmt.Sprintf("Expect to apply buildin len() on %s", acts), []labeledOutput{
 			{
 				label:   labelMessages,
 				content: formatExtras(extras...),
 			},
+		}, arguments{
+			"v":      v,
+			"length": length,
 		})
 	}
 
 	if n != length {
-		_, acts := toString(nil, v)
+		_, acts := configOf(t).toString(nil, v)
 
 		return Errorf(t, fmt.Sprintf("Expect %s to have %d item(s)", acts, length), []labeledOutput{
 			{
@@ -663,6 +858,9 @@ func Len(t TestingT, v interface{}, length int, extras ...interface{}) bool {
 				label:   "+received",
 				content: strconv.Itoa(n),
 			},
+		}, arguments{
+			"v":      v,
+			"length": length,
 		})
 	}
 
@@ -675,11 +873,15 @@ func Len(t TestingT, v interface{}, length int, extras ...interface{}) bool {
 //
 // Returns whether the assertion was successful (true) or not (false).
 func InDelta(t TestingT, expected, actual interface{}, delta float64, extras ...interface{}) bool {
-	expf, expok := toFloat(expected)
-	actf, actok := toFloat(actual)
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	exp, expok := numeralOf(expected)
+	act, actok := numeralOf(actual)
 
 	if !expok || !actok {
-		exps, acts := toString(expected, actual)
+		exps, acts := configOf(t).toString(expected, actual)
 
 		return Errorf(t, "Parameters must be numerical", []labeledOutput{
 			{
@@ -694,11 +896,21 @@ func InDelta(t TestingT, expected, actual interface{}, delta float64, extras ...
 				label:   "+received",
 				content: acts,
 			},
+		}, arguments{
+			"expected": expected,
+			"actual":   actual,
+			"delta":    delta,
 		})
 	}
 
-	if math.IsNaN(expf) || math.IsNaN(actf) {
-		exps, acts := toString(expf, actf)
+	policy := configOf(t).FloatPolicy
+
+	if exp.nan || act.nan {
+		if exp.nan && act.nan && policy&FloatNaNEqual != 0 {
+			return true
+		}
+
+		exps, acts := configOf(t).toString(expected, actual)
 
 		return Errorf(t, "Both expected and actual values must NOT be NaN", []labeledOutput{
 			{
@@ -713,26 +925,45 @@ func InDelta(t TestingT, expected, actual interface{}, delta float64, extras ...
 				label:   "+received",
 				content: acts,
 			},
+		}, arguments{
+			"expected": expected,
+			"actual":   actual,
+			"delta":    delta,
 		})
 	}
 
-	value := expf - actf
-	if value < -delta || value > delta {
-		exps, acts := toString(expected, actual)
+	var value string
+	if exp.isInf() || act.isInf() {
+		if exp.equal(act) && policy&FloatInfUnequal == 0 {
+			return true
+		}
 
-		return Errorf(t, fmt.Sprintf("Expect the delta between two numbers within %v", delta), []labeledOutput{
-			{
-				label:   labelMessages,
-				content: formatExtras(extras...),
-			},
-			{
-				label:   "+calculated:",
-				content: fmt.Sprintf("%s - %s = %v", exps, acts, value),
-			},
-		})
+		value = fmt.Sprint(exp.float64() - act.float64())
+	} else {
+		// compare in arbitrary precision, int64 and uint64 lose precision as float64
+		if !math.IsNaN(delta) && delta >= 0 && exp.distance(act).Cmp(big.NewFloat(delta)) <= 0 {
+			return true
+		}
+
+		value = exp.sub(act).String()
 	}
 
-	return true
+	exps, acts := configOf(t).toString(expected, actual)
+
+	return Errorf(t, fmt.Sprintf("Expect the delta between two numbers within %v", delta), []labeledOutput{
+		{
+			label:   labelMessages,
+			content: formatExtras(extras...),
+		},
+		{
+			label:   "+calculated:",
+			content: fmt.Sprintf("%s - %s = %s", exps, acts, value),
+		},
+	}, arguments{
+		"expected": expected,
+		"actual":   actual,
+		"delta":    delta,
+	})
 }
 
 // WithinDuration asserts that the two times are within duration delta of each other.
@@ -741,9 +972,13 @@ func InDelta(t TestingT, expected, actual interface{}, delta float64, extras ...
 //
 // Returns whether the assertion was successful (true) or not (false).
 func WithinDuration(t TestingT, expected, actual time.Time, delta time.Duration, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	value := expected.Sub(actual)
 	if value < -delta || value > delta {
-		exps, acts := toString(expected, actual)
+		exps, acts := configOf(t).toString(expected, actual)
 
 		return Errorf(t, fmt.Sprintf("Expect the deviation between two times within %v", delta), []labeledOutput{
 			{
@@ -754,6 +989,10 @@ func WithinDuration(t TestingT, expected, actual time.Time, delta time.Duration,
 				label:   "+calculated:",
 				content: fmt.Sprintf("%s.Sub(%s) = %v", exps, acts, value),
 			},
+		}, arguments{
+			"expected": expected,
+			"actual":   actual,
+			"delta":    delta,
 		})
 	}
 
@@ -767,9 +1006,13 @@ func WithinDuration(t TestingT, expected, actual time.Time, delta time.Duration,
 //
 // Returns whether the assertion was successful (true) or not (false).
 func Error(t TestingT, v interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	_, ok := v.(error)
 	if !ok {
-		_, acts := toString(nil, v)
+		_, acts := configOf(t).toString(nil, v)
 
 		return Errorf(t, "Expect to be an error", []labeledOutput{
 			{
@@ -784,6 +1027,8 @@ func Error(t TestingT, v interface{}, extras ...interface{}) bool {
 				label:   "+received",
 				content: fmt.Sprintf("_, ok := %s.(error); ok == false", acts),
 			},
+		}, arguments{
+			"v": v,
 		})
 	}
 
@@ -797,9 +1042,13 @@ func Error(t TestingT, v interface{}, extras ...interface{}) bool {
 //
 // Returns whether the assertion was successful (true) or not (false).
 func NotError(t TestingT, v interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	_, ok := v.(error)
 	if ok {
-		_, acts := toString(nil, v)
+		_, acts := configOf(t).toString(nil, v)
 
 		return Errorf(t, "Expect to be NOT an error", []labeledOutput{
 			{
@@ -814,6 +1063,8 @@ func NotError(t TestingT, v interface{}, extras ...interface{}) bool {
 				label:   "+received",
 				content: fmt.Sprintf("_, ok := %s.(error); ok == true", acts),
 			},
+		}, arguments{
+			"v": v,
 		})
 	}
 
@@ -828,6 +1079,10 @@ func NotError(t TestingT, v interface{}, extras ...interface{}) bool {
 //
 // Returns whether the assertion was successful (true) or not (false).
 func EqualErrors(t TestingT, actualErr, expectedErr interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	if !Error(t, actualErr, extras...) {
 		return false
 	}
@@ -856,9 +1111,12 @@ func EqualErrors(t TestingT, actualErr, expectedErr interface{}, extras ...inter
 				content: formatExtras(extras...),
 			},
 			{
-				label:   "Diff",
-				content: diff(expected, actual),
+				label:   labelDiff,
+				content: configOf(t).diff(expected, actual),
 			},
+		}, arguments{
+			"actualErr":   actualErr,
+			"expectedErr": expectedErr,
 		})
 	}
 
@@ -873,6 +1131,10 @@ func EqualErrors(t TestingT, actualErr, expectedErr interface{}, extras ...inter
 //
 // Returns whether the assertion was successful (true) or not (false).
 func Panics(t TestingT, f PanicRecover, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	paniced, _ := recovery(f)
 	if !paniced {
 		return Errorf(t, "Expect to panic with invocation", []labeledOutput{
@@ -898,6 +1160,10 @@ func Panics(t TestingT, f PanicRecover, extras ...interface{}) bool {
 //
 // Returns whether the assertion was successful (true) or not (false).
 func NotPanics(t TestingT, f PanicRecover, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	paniced, err := recovery(f)
 	if paniced {
 		return Errorf(t, "Expect to NOT panic with invocation", []labeledOutput{
@@ -921,10 +1187,14 @@ func NotPanics(t TestingT, f PanicRecover, extras ...interface{}) bool {
 //
 // Returns whether the assertion was successful (true) or not (false).
 func JSONContains(t TestingT, jsonData, searchKeyPath string, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	var jsonValue interface{}
 
 	if err := json.Unmarshal([]byte(jsonData), &jsonValue); err != nil {
-		exps, _ := toString(jsonData, nil)
+		exps, _ := configOf(t).toString(jsonData, nil)
 
 		return Errorf(t, "Expect data should be valid json", []labeledOutput{
 			{
@@ -939,6 +1209,9 @@ func JSONContains(t TestingT, jsonData, searchKeyPath string, extras ...interfac
 				label:   "+JSON Parse",
 				content: err.Error(),
 			},
+		}, arguments{
+			"jsonData":      jsonData,
+			"searchKeyPath": searchKeyPath,
 		})
 	}
 
@@ -976,7 +1249,7 @@ func JSONContains(t TestingT, jsonData, searchKeyPath string, extras ...interfac
 		}
 	}
 	if err != nil {
-		exps, _ := toString(jsonData, nil)
+		exps, _ := configOf(t).toString(jsonData, nil)
 
 		return Errorf(t, fmt.Sprintf("Expect data should contain json key %s", searchKeyPath), []labeledOutput{
 			{
@@ -987,6 +1260,9 @@ func JSONContains(t TestingT, jsonData, searchKeyPath string, extras ...interfac
 				label:   "+JSON",
 				content: exps,
 			},
+		}, arguments{
+			"jsonData":      jsonData,
+			"searchKeyPath": searchKeyPath,
 		})
 	}
 
@@ -999,10 +1275,14 @@ func JSONContains(t TestingT, jsonData, searchKeyPath string, extras ...interfac
 //
 // Returns whether the assertion was successful (true) or not (false).
 func JSONEqualValues(t TestingT, jsonData, searchKeyPath string, expected interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
 	var jsonValue interface{}
 
 	if err := json.Unmarshal([]byte(jsonData), &jsonValue); err != nil {
-		exps, _ := toString(jsonData, nil)
+		exps, _ := configOf(t).toString(jsonData, nil)
 
 		return Errorf(t, "Expect data should be valid json", []labeledOutput{
 			{
@@ -1017,6 +1297,10 @@ func JSONEqualValues(t TestingT, jsonData, searchKeyPath string, expected interf
 				label:   "+JSON Parse",
 				content: err.Error(),
 			},
+		}, arguments{
+			"jsonData":      jsonData,
+			"searchKeyPath": searchKeyPath,
+			"expected":      expected,
 		})
 	}
 
@@ -1054,7 +1338,7 @@ func JSONEqualValues(t TestingT, jsonData, searchKeyPath string, expected interf
 		}
 	}
 	if err != nil {
-		exps, _ := toString(jsonData, nil)
+		exps, _ := configOf(t).toString(jsonData, nil)
 
 		return Errorf(t, fmt.Sprintf("Expect data should contain json key %s", searchKeyPath), []labeledOutput{
 			{
@@ -1065,6 +1349,10 @@ func JSONEqualValues(t TestingT, jsonData, searchKeyPath string, expected interf
 				label:   "+JSON",
 				content: exps,
 			},
+		}, arguments{
+			"jsonData":      jsonData,
+			"searchKeyPath": searchKeyPath,
+			"expected":      expected,
 		})
 	}
 
@@ -1103,9 +1391,13 @@ func JSONEqualValues(t TestingT, jsonData, searchKeyPath string, expected interf
 				content: formatExtras(extras...),
 			},
 			{
-				label:   "Diff",
-				content: diff(expected, string(actual)),
+				label:   labelDiff,
+				content: configOf(t).diff(expected, string(actual)),
 			},
+		}, arguments{
+			"jsonData":      jsonData,
+			"searchKeyPath": searchKeyPath,
+			"expected":      expected,
 		})
 	}
 
@@ -6,6 +6,7 @@ import (
 	"io"
 	"math"
 	"regexp"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -193,14 +194,21 @@ func TestEqualFormatting(t *testing.T) {
 		{
 			expected: "want",
 			actual:   "got",
-			message:  "\tassertions.go:[0-9]+:\\s+?Error Trace:\t(\\S+:[0-9]+\\s+)+?Error:\tExpect to be equal\\s+Diff:\\s+--- Expected\\s+\\+\\+\\+ Actual\\s+?",
+			message:  "^\\s*Error Trace:\t(\\S+:[0-9]+ \\S+\\s+)+?Error:\tExpect to be equal\\s+Diff:\\s+--- Expected\\s+\\+\\+\\+ Actual\\s+?",
 		},
-		{expected: "want", actual: "got", extras: []interface{}{"Hello, %s", "world!"}, message: "\tassertions.go:[0-9]+:\\s+Hello, world!\\s+Error Trace:\t(\\S+:[0-9]+\\s+)+?Error:\tExpect to be equal\\s+Diff:\\s+--- Expected\\s+\\+\\+\\+ Actual\\s+?"},
+		{expected: "want", actual: "got", extras: []interface{}{"Hello, %s", "world!"}, message: "^\\s*Hello, world!\\s+Error Trace:\t(\\S+:[0-9]+ \\S+\\s+)+?Error:\tExpect to be equal\\s+Diff:\\s+--- Expected\\s+\\+\\+\\+ Actual\\s+?"},
 	} {
-		mockT := &gospec{}
+		mockT := new(RecordingT)
 
+		_, _, line, _ := runtime.Caller(0)
 		Equal(mockT, currCase.expected, currCase.actual, currCase.extras...)
 		Match(t, regexp.MustCompile(currCase.message), mockT.String(), "Case %d", i)
+
+		// the location of the failed assertion
+		records := mockT.Records()
+		if Len(t, records, 1, "Case %d", i) && NotEmpty(t, records[0].Frames, "Case %d", i) {
+			Equal(t, TraceFrame{File: "assertions_test.go", Line: line + 1, Function: "github.com/dolab/gospec.TestEqualFormatting"}, records[0].Frames[0], "Case %d", i)
+		}
 	}
 }
 
@@ -301,6 +309,15 @@ func TestEqualValues(t *testing.T) {
 	}
 }
 
+func TestEqualBytes(t *testing.T) {
+	mockT := new(testing.T)
+
+	True(t, EqualBytes(mockT, []byte{0x01, 0xff}, []byte{0x01, 0xff}))
+	True(t, EqualBytes(mockT, nil, []byte{}))
+	False(t, EqualBytes(mockT, []byte{0x01, 0xff}, []byte{0x01, 0xfe}))
+	False(t, EqualBytes(mockT, []byte{0x01, 0xff}, []byte{0x01}))
+}
+
 func TestEqualJSON(t *testing.T) {
 	mockT := new(testing.T)
 
@@ -1068,7 +1085,7 @@ func TestNotError(t *testing.T) {
 		return err
 	}()
 	if err == nil { // err is not nil here!
-		t.Errorf("Error should be nil due to empty interface", err)
+		t.Errorf("Error should be nil due to empty interface: %v", err)
 	}
 
 	False(t, NotError(mockT, err), "NotError should fail with empty error interface")
@@ -0,0 +1,146 @@
+package gospec
+
+import (
+	"context"
+	"os"
+	"runtime"
+)
+
+// underlyingT returns the TestingT wrapped by WithConfig, or t itself, for detecting its capabilities.
+func underlyingT(t TestingT) TestingT {
+	if ct, ok := t.(*configuredT); ok {
+		return ct.TestingT
+	}
+
+	return t
+}
+
+// Helper forwards to the TestingT wrapped by WithConfig, if it marks test helpers.
+func (ct *configuredT) Helper() {
+	if h := helperOf(ct.TestingT); h != nil {
+		h.Helper()
+	}
+}
+
+// FailNow forwards to the TestingT wrapped by WithConfig, or stops the goroutine as testing.T does
+// if it can't stop the test.
+func (ct *configuredT) FailNow() {
+	if !failNow(ct.TestingT) {
+		runtime.Goexit()
+	}
+}
+
+// Log forwards to the TestingT wrapped by WithConfig, if it logs.
+func (ct *configuredT) Log(args ...interface{}) {
+	if l, ok := ct.TestingT.(LogT); ok {
+		l.Log(args...)
+	}
+}
+
+// Name returns the name of the TestingT wrapped by WithConfig, or an empty string if it is not named.
+func (ct *configuredT) Name() string {
+	return nameOf(ct.TestingT)
+}
+
+// Cleanup forwards to the TestingT wrapped by WithConfig, if it supports cleanups.
+func (ct *configuredT) Cleanup(fn func()) {
+	cleanupOf(ct.TestingT, fn)
+}
+
+// Context returns the context of the TestingT wrapped by WithConfig, or context.Background().
+func (ct *configuredT) Context() context.Context {
+	return contextOf(ct.TestingT)
+}
+
+// TempDir returns the temp dir of the TestingT wrapped by WithConfig, or creates one in the default
+// temp dir of os, which is removed by Cleanup if supported.
+func (ct *configuredT) TempDir() string {
+	if dir := tempDirOf(ct.TestingT); dir != "" {
+		return dir
+	}
+
+	dir, err := os.MkdirTemp("", "gospec-")
+	if err != nil {
+		ct.Errorf("TempDir: %v", err)
+		ct.FailNow()
+	}
+
+	ct.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+
+	return dir
+}
+
+// helperOf returns t, or the TestingT wrapped by WithConfig, if it marks test helpers.
+// It returns nil otherwise, so assertions mark themselves with
+//
+//	if h := helperOf(t); h != nil {
+//		h.Helper()
+//	}
+//
+// rather than calling Helper within helperOf, which would mark helperOf instead.
+func helperOf(t TestingT) HelperT {
+	h, _ := underlyingT(t).(HelperT)
+	return h
+}
+
+// nameOf returns the name of the test, or an empty string if t is not named.
+func nameOf(t TestingT) string {
+	if namer, ok := underlyingT(t).(NameT); ok {
+		return namer.Name()
+	}
+
+	return ""
+}
+
+// failNow stops the test if t supports it, and returns false otherwise.
+func failNow(t TestingT) bool {
+	if f, ok := underlyingT(t).(FailNowT); ok {
+		f.FailNow()
+		return true
+	}
+
+	return false
+}
+
+// cleanupOf registers fn to run after the test, and returns false if t does not support it.
+func cleanupOf(t TestingT, fn func()) bool {
+	if c, ok := underlyingT(t).(CleanupT); ok {
+		c.Cleanup(fn)
+		return true
+	}
+
+	return false
+}
+
+// contextOf returns the context of the test, or context.Background() if t has none.
+func contextOf(t TestingT) context.Context {
+	if c, ok := underlyingT(t).(ContextT); ok {
+		if ctx := c.Context(); ctx != nil {
+			return ctx
+		}
+	}
+
+	return context.Background()
+}
+
+// tempDirOf returns the temp dir of the test, or an empty string for the default temp dir of os.
+func tempDirOf(t TestingT) string {
+	if d, ok := underlyingT(t).(TempDirT); ok {
+		return d.TempDir()
+	}
+
+	return ""
+}
+
+// Require returns a TestingT stopping the test by FailNow after the first failure of assertions,
+// if the underlying t supports FailNow. Other settings of the config of t are kept.
+//
+//	require := gospec.Require(t)
+//	gospec.NotError(require, err)
+func Require(t TestingT) TestingT {
+	return WithConfig(t, func(c *Config) {
+		c.FailNow = true
+	})
+}
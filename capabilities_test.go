@@ -0,0 +1,205 @@
+package gospec
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+// testingErrorfT implements Errorf only.
+type testingErrorfT struct {
+	errors []string
+}
+
+func (et *testingErrorfT) Errorf(format string, args ...interface{}) {
+	et.errors = append(et.errors, format)
+}
+
+// testingCapableT implements all optional capabilities.
+type testingCapableT struct {
+	testingErrorfT
+
+	failed   int
+	cleanups []func()
+	ctx      context.Context
+	dir      string
+	tempDirs int
+}
+
+func (ct *testingCapableT) Helper() {}
+
+func (ct *testingCapableT) FailNow() {
+	ct.failed++
+}
+
+func (ct *testingCapableT) Name() string {
+	return "TestCapable"
+}
+
+func (ct *testingCapableT) Cleanup(fn func()) {
+	ct.cleanups = append(ct.cleanups, fn)
+}
+
+func (ct *testingCapableT) Context() context.Context {
+	return ct.ctx
+}
+
+func (ct *testingCapableT) TempDir() string {
+	ct.tempDirs++
+	return ct.dir
+}
+
+func Test_Capabilities(t *testing.T) {
+	mockT := new(testingErrorfT)
+
+	Nil(t, helperOf(mockT))
+	Equal(t, "", nameOf(mockT))
+	False(t, failNow(mockT))
+	False(t, cleanupOf(mockT, func() {}))
+	Equal(t, context.Background(), contextOf(mockT))
+	Equal(t, "", tempDirOf(mockT))
+
+	// minimal implementations still work
+	False(t, Equal(mockT, 1, 2))
+	Equal(t, 1, len(mockT.errors))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	capableT := &testingCapableT{
+		ctx: ctx,
+		dir: t.TempDir(),
+	}
+
+	// capabilities are detected through WithConfig
+	configuredT := WithConfig(capableT)
+
+	NotNil(t, helperOf(configuredT))
+	Equal(t, "TestCapable", nameOf(configuredT))
+	Equal(t, ctx, contextOf(configuredT))
+	Equal(t, capableT.dir, tempDirOf(configuredT))
+	True(t, cleanupOf(configuredT, func() {}))
+	Equal(t, 1, len(capableT.cleanups))
+	True(t, failNow(configuredT))
+	Equal(t, 1, capableT.failed)
+}
+
+func TestWithConfig_Capabilities(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	capableT := &testingCapableT{
+		ctx: ctx,
+		dir: t.TempDir(),
+	}
+
+	// capabilities of the wrapped TestingT are forwarded
+	requireT := Require(capableT)
+
+	requireT.(HelperT).Helper()
+	Equal(t, "TestCapable", requireT.(NameT).Name())
+	Equal(t, ctx, requireT.(ContextT).Context())
+	Equal(t, capableT.dir, requireT.(TempDirT).TempDir())
+
+	requireT.(CleanupT).Cleanup(func() {})
+	Equal(t, 1, len(capableT.cleanups))
+
+	requireT.(FailNowT).FailNow()
+	Equal(t, 1, capableT.failed)
+
+	logger := new(testingLogger)
+	WithConfig(logger).(LogT).Log("Hello")
+	Equal(t, []string{"Hello"}, logger.logs)
+
+	// fallbacks without capabilities
+	configuredT := WithConfig(new(testingErrorfT))
+
+	configuredT.(HelperT).Helper()
+	configuredT.(CleanupT).Cleanup(func() {})
+	Equal(t, "", configuredT.(NameT).Name())
+	Equal(t, context.Background(), configuredT.(ContextT).Context())
+
+	dir := configuredT.(TempDirT).TempDir()
+	defer os.RemoveAll(dir)
+	DirExists(t, dir)
+
+	done := make(chan bool)
+	go func() {
+		defer close(done)
+
+		configuredT.(FailNowT).FailNow()
+		done <- true
+	}()
+	False(t, <-done)
+}
+
+func TestRequire(t *testing.T) {
+	capableT := new(testingCapableT)
+
+	requireT := Require(capableT)
+	True(t, configOf(requireT).FailNow)
+	False(t, configOf(capableT).FailNow)
+
+	True(t, Equal(requireT, 1, 1))
+	Equal(t, 0, capableT.failed)
+
+	False(t, Equal(requireT, 1, 2))
+	Equal(t, 1, capableT.failed)
+
+	// other settings are kept
+	requireT = Require(WithConfig(capableT, func(c *Config) {
+		c.DiffContext = 5
+	}))
+	Equal(t, 5, configOf(requireT).DiffContext)
+	Equal(t, GetConfig().TraceSkipDirs, configOf(requireT).TraceSkipDirs)
+	Equal(t, GetConfig().Expressions, configOf(requireT).Expressions)
+	True(t, configOf(requireT).FailNow)
+
+	// without FailNow
+	mockT := new(testingErrorfT)
+
+	False(t, Equal(Require(mockT), 1, 2))
+	Equal(t, 1, len(mockT.errors))
+}
+
+func TestReaderContains_Spool(t *testing.T) {
+	capableT := &testingCapableT{
+		ctx: context.Background(),
+		dir: t.TempDir(),
+	}
+
+	// no temp dir for data kept in memory
+	r, ok := ReaderContains(capableT, strings.NewReader("x"), "y")
+	False(t, ok)
+	NotNil(t, r)
+	Equal(t, 0, capableT.tempDirs)
+
+	capableT.cleanups = nil
+
+	data := strings.Repeat("x", readerSpoolSize+1)
+
+	r, ok = ReaderContains(capableT, strings.NewReader(data), "y")
+	False(t, ok)
+	NotNil(t, r)
+	Equal(t, 1, capableT.tempDirs)
+	Equal(t, 1, len(capableT.cleanups))
+
+	files, err := os.ReadDir(capableT.dir)
+	Nil(t, err)
+	Equal(t, 1, len(files))
+
+	for _, fn := range capableT.cleanups {
+		fn()
+	}
+
+	files, err = os.ReadDir(capableT.dir)
+	Nil(t, err)
+	Equal(t, 0, len(files))
+
+	// closing after cleanup is safe
+	r.Close()
+
+	_, err = os.Stat(capableT.dir)
+	Nil(t, err)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ansiPattern matches ANSI escape codes of colorized output, which are invalid characters of XML.
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*[A-Za-z]")
+
+type junitTestSuites struct {
+	XMLName xml.Name          `xml:"testsuites"`
+	Suites  []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Skipped   int              `xml:"skipped,attr"`
+	Time      string           `xml:"time,attr"`
+	Timestamp string           `xml:"timestamp,attr,omitempty"`
+	Cases     []*junitTestCase `xml:"testcase"`
+	SystemOut *junitOutput     `xml:"system-out,omitempty"`
+}
+
+type junitTestCase struct {
+	Classname string          `xml:"classname,attr"`
+	Name      string          `xml:"name,attr"`
+	Time      string          `xml:"time,attr"`
+	Failures  []*junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped   `xml:"skipped,omitempty"`
+	SystemOut *junitOutput    `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Content string `xml:",cdata"`
+}
+
+type junitOutput struct {
+	Content string `xml:",cdata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// WriteJUnit writes suites as JUnit XML to w.
+func WriteJUnit(w io.Writer, suites []*Suite) error {
+	report := junitTestSuites{}
+
+	for _, suite := range suites {
+		total, failures, skipped := suite.Counts()
+
+		xsuite := &junitTestSuite{
+			Name:     suite.Package,
+			Tests:    total,
+			Failures: failures,
+			Skipped:  skipped,
+			Time:     formatSeconds(suite.Elapsed),
+		}
+		if !suite.Time.IsZero() {
+			xsuite.Timestamp = suite.Time.Format(time.RFC3339)
+		}
+
+		for _, tc := range suite.Tests {
+			xcase := &junitTestCase{
+				Classname: tc.Package,
+				Name:      tc.Name,
+				Time:      formatSeconds(tc.Elapsed),
+			}
+
+			switch {
+			case tc.Failed():
+				xcase.SystemOut = &junitOutput{
+					Content: stripANSI(tc.Output),
+				}
+
+				for _, failure := range tc.Failures {
+					xcase.Failures = append(xcase.Failures, &junitFailure{
+						Message: stripANSI(failure.Summary()),
+						Type:    "gospec",
+						Content: stripANSI(failure.Details()),
+					})
+				}
+
+				// failed without gospec failures, e.g. t.Fatal or panic
+				if len(xcase.Failures) == 0 {
+					xcase.Failures = append(xcase.Failures, &junitFailure{
+						Message: "Failed",
+						Type:    "go test",
+						Content: stripANSI(tc.Output),
+					})
+				}
+
+			case tc.Skipped():
+				xcase.Skipped = &junitSkipped{
+					Message: stripANSI(trimFrames(tc.Output)),
+				}
+
+			}
+
+			xsuite.Cases = append(xsuite.Cases, xcase)
+		}
+
+		report.Suites = append(report.Suites, xsuite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// trimFrames removes "=== RUN" and "--- SKIP" lines framing the test output.
+func trimFrames(output string) string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "=== ") || strings.HasPrefix(line, "--- ") {
+			continue
+		}
+
+		lines = append(lines, strings.TrimSpace(line))
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// stripANSI removes ANSI escape codes from s.
+func stripANSI(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
+
+func formatSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
@@ -0,0 +1,47 @@
+// Command gospec provides tools working with outputs of gospec assertions.
+//
+// Usage:
+//
+//	go test -json ./... | gospec report -format junit -o report.xml
+//	go test -json ./... | gospec report -format tap
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `Usage: gospec <command> [arguments]
+
+The commands are:
+
+	report	convert "go test -json" output into JUnit XML or TAP
+
+Use "gospec <command> -h" for more information about a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "report":
+		err = runReport(os.Args[2:])
+
+	case "help", "-h", "--help":
+		fmt.Fprint(os.Stdout, usage)
+
+	default:
+		fmt.Fprintf(os.Stderr, "gospec: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gospec: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,447 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dolab/gospec"
+)
+
+const (
+	labelErrorTrace = "Error Trace"
+	labelError      = "Error"
+	labelDiff       = "Diff"
+
+	// packageCaseName is the test case name used for failures reported without a test,
+	// such as build failures.
+	packageCaseName = "(package)"
+)
+
+var (
+	// rxFileLine matches the file:line decoration added by testing.T
+	rxFileLine = regexp.MustCompile(`^\s*[^\s:]+\.go:\d+: ?`)
+
+	// rxLabel matches labeled lines of gospec failure output
+	rxLabel = regexp.MustCompile(`^ *([+-]?[A-Za-z][A-Za-z0-9 ]*?):?:\t(.*)$`)
+)
+
+// testEvent is the event emitted by `go test -json`, see `go doc test2json`.
+type testEvent struct {
+	Time    time.Time
+	Action  string
+	Package string
+	Test    string
+	Elapsed float64
+	Output  string
+}
+
+// Failure is a gospec failure extracted from test output.
+type Failure struct {
+	Message string
+	Error   string
+	Trace   string
+	Diff    string
+	Labels  []gospec.FailureLabel
+}
+
+// Summary returns a one line description of the failure.
+func (failure *Failure) Summary() string {
+	if failure.Message == "" {
+		return failure.Error
+	}
+
+	if failure.Error == "" {
+		return failure.Message
+	}
+
+	return failure.Message + ": " + failure.Error
+}
+
+// Details returns the full text of the failure.
+func (failure *Failure) Details() string {
+	buf := new(strings.Builder)
+
+	if failure.Trace != "" {
+		fmt.Fprintf(buf, "%s: %s\n", labelErrorTrace, failure.Trace)
+	}
+	if failure.Error != "" {
+		fmt.Fprintf(buf, "%s: %s\n", labelError, failure.Error)
+	}
+	if failure.Message != "" {
+		fmt.Fprintf(buf, "Message: %s\n", failure.Message)
+	}
+
+	for _, label := range failure.Labels {
+		if label.Label == labelDiff {
+			continue
+		}
+
+		fmt.Fprintf(buf, "%s: %s\n", label.Label, label.Content)
+	}
+
+	if failure.Diff != "" {
+		fmt.Fprintf(buf, "%s:\n%s\n", labelDiff, failure.Diff)
+	}
+
+	return buf.String()
+}
+
+// TestCase is the result of a test.
+type TestCase struct {
+	Package  string
+	Name     string
+	Action   string
+	Elapsed  float64
+	Output   string
+	Failures []*Failure
+}
+
+// Failed returns true if the test case failed.
+func (tc *TestCase) Failed() bool {
+	return tc.Action == "fail"
+}
+
+// Skipped returns true if the test case was skipped.
+func (tc *TestCase) Skipped() bool {
+	return tc.Action == "skip"
+}
+
+// Suite is the result of a package.
+type Suite struct {
+	Package string
+	Action  string
+	Elapsed float64
+	Time    time.Time
+	Output  string
+	Tests   []*TestCase
+}
+
+// Counts returns numbers of total, failed and skipped test cases of the suite.
+func (suite *Suite) Counts() (total, failures, skipped int) {
+	for _, tc := range suite.Tests {
+		total++
+
+		switch {
+		case tc.Failed():
+			failures++
+
+		case tc.Skipped():
+			skipped++
+		}
+	}
+
+	return
+}
+
+// ParseEvents reads `go test -json` output from r and returns suites grouped by package.
+func ParseEvents(r io.Reader) ([]*Suite, error) {
+	var (
+		suites  []*Suite
+		byName  = map[string]*Suite{}
+		cases   = map[string]*TestCase{}
+		outputs = map[string]*strings.Builder{}
+	)
+
+	getSuite := func(pkg string, ts time.Time) *Suite {
+		suite, ok := byName[pkg]
+		if !ok {
+			suite = &Suite{
+				Package: pkg,
+				Time:    ts,
+			}
+
+			byName[pkg] = suite
+			suites = append(suites, suite)
+			outputs[pkg] = new(strings.Builder)
+		}
+
+		return suite
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+
+		var event testEvent
+		if err := json.Unmarshal(line, &event); err != nil {
+			return nil, fmt.Errorf("invalid test event %q: %v", line, err)
+		}
+
+		suite := getSuite(event.Package, event.Time)
+
+		if event.Test == "" {
+			switch event.Action {
+			case "output":
+				outputs[event.Package].WriteString(event.Output)
+
+			case "pass", "fail", "skip":
+				suite.Action = event.Action
+				suite.Elapsed = event.Elapsed
+			}
+
+			continue
+		}
+
+		key := event.Package + "\x00" + event.Test
+
+		tc, ok := cases[key]
+		if !ok {
+			tc = &TestCase{
+				Package: event.Package,
+				Name:    event.Test,
+			}
+
+			cases[key] = tc
+			outputs[key] = new(strings.Builder)
+			suite.Tests = append(suite.Tests, tc)
+		}
+
+		switch event.Action {
+		case "output":
+			outputs[key].WriteString(event.Output)
+
+		case "pass", "fail", "skip":
+			tc.Action = event.Action
+			tc.Elapsed = event.Elapsed
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, suite := range suites {
+		suite.Output = outputs[suite.Package].String()
+
+		failed := false
+		for _, tc := range suite.Tests {
+			tc.Output = outputs[tc.Package+"\x00"+tc.Name].String()
+			tc.Failures = ParseFailures(tc.Output)
+
+			if tc.Failed() {
+				failed = true
+			}
+		}
+
+		// NOTE: a failed package without failed tests is usually a build or setup failure,
+		// report it as a failed test case for not losing it.
+		if suite.Action == "fail" && !failed {
+			suite.Tests = append(suite.Tests, &TestCase{
+				Package: suite.Package,
+				Name:    packageCaseName,
+				Action:  "fail",
+				Elapsed: suite.Elapsed,
+				Output:  suite.Output,
+				Failures: []*Failure{
+					{
+						Error: strings.TrimSpace(suite.Output),
+					},
+				},
+			})
+		}
+	}
+
+	return suites, nil
+}
+
+// ParseFailures extracts gospec failures from text output of a test.
+//
+// Both text blocks of gospec.Errorf and JSON records logged by gospec.JSONReporter are recognized.
+func ParseFailures(output string) (failures []*Failure) {
+	var (
+		failure *Failure
+		label   *gospec.FailureLabel
+		message []string
+		logged  bool // whether the last failure is from a JSON record
+	)
+
+	flush := func() {
+		if failure == nil {
+			return
+		}
+
+		labels := failure.Labels[:0]
+		for _, label := range failure.Labels {
+			label.Content = strings.TrimRight(label.Content, "\n")
+
+			switch label.Label {
+			case labelErrorTrace:
+				failure.Trace = label.Content
+				continue
+
+			case labelError:
+				failure.Error = label.Content
+				continue
+
+			case labelDiff:
+				failure.Diff = label.Content
+
+			}
+
+			labels = append(labels, label)
+		}
+		failure.Labels = labels
+
+		failures = append(failures, failure)
+		failure = nil
+		label = nil
+		logged = false
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		// JSON records of gospec.JSONReporter
+		if idx := strings.Index(line, gospec.JSONReportPrefix); idx >= 0 {
+			var record gospec.FailureRecord
+			if err := json.Unmarshal([]byte(line[idx+len(gospec.JSONReportPrefix):]), &record); err == nil {
+				flush()
+
+				// the record follows the text block of the same failure, prefer the structured one
+				if n := len(failures); n > 0 && !logged && failures[n-1].Error == record.Error {
+					failures = failures[:n-1]
+				}
+
+				failures = append(failures, &Failure{
+					Message: record.Message,
+					Error:   record.Error,
+					Trace:   strings.Join(record.Trace, "\n"),
+					Diff:    record.Diff,
+					Labels:  record.Labels,
+				})
+
+				logged = true
+				message = nil
+				continue
+			}
+		}
+
+		// strip the padding added by go test and the "\r" hack of gospec
+		text := line
+		if idx := strings.LastIndex(text, "\r"); idx >= 0 {
+			text = text[idx+1:]
+		}
+		text = strings.TrimPrefix(text, "\t")
+
+		if matches := rxLabel.FindStringSubmatch(text); matches != nil && (failure != nil || matches[1] == labelErrorTrace) {
+			if matches[1] == labelErrorTrace {
+				flush()
+
+				failure = &Failure{
+					Message: strings.TrimSpace(strings.Join(message, "\n")),
+				}
+				message = nil
+			}
+
+			failure.Labels = append(failure.Labels, gospec.FailureLabel{
+				Label:   matches[1],
+				Content: matches[2],
+			})
+			label = &failure.Labels[len(failure.Labels)-1]
+
+			continue
+		}
+
+		// continued content aligned by spaces and a tab
+		content := strings.TrimPrefix(strings.TrimLeft(text, " "), "\t")
+
+		switch {
+		case rxFileLine.MatchString(line):
+			// a new output of testing.T, which may be the message of the next failure
+			flush()
+
+			message = []string{rxFileLine.ReplaceAllString(line, "")}
+
+		case strings.Contains(line, "\r"):
+			if label != nil {
+				label.Content += "\n" + content
+			} else if message != nil {
+				message = append(message, content)
+			}
+
+		default:
+			flush()
+
+			message = nil
+		}
+	}
+
+	flush()
+
+	return
+}
+
+func runReport(args []string) error {
+	flags := flag.NewFlagSet("report", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), "Usage: gospec report [-format junit|tap] [-o output] [input]\n\n")
+		fmt.Fprint(flags.Output(), "Report reads \"go test -json\" output from input (default stdin) and writes JUnit XML or TAP.\n\n")
+		flags.PrintDefaults()
+	}
+
+	var (
+		format = flags.String("format", "junit", "report format, junit or tap")
+		output = flags.String("o", "", "write report to the file instead of stdout")
+	)
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+
+		return err
+	}
+
+	var w func(io.Writer, []*Suite) error
+	switch *format {
+	case "junit", "xml":
+		w = WriteJUnit
+
+	case "tap":
+		w = WriteTAP
+
+	default:
+		return fmt.Errorf("unknown report format %q", *format)
+	}
+
+	var r io.Reader = os.Stdin
+	if flags.NArg() > 0 && flags.Arg(0) != "-" {
+		file, err := os.Open(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		r = file
+	}
+
+	suites, err := ParseEvents(r)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return w(os.Stdout, suites)
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+
+	if err := w(file, suites); err != nil {
+		file.Close()
+
+		return err
+	}
+
+	return file.Close()
+}
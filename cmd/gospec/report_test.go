@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dolab/gospec"
+)
+
+func parseTestdata(t *testing.T) []*Suite {
+	file, err := os.Open("testdata/failures.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	suites, err := ParseEvents(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return suites
+}
+
+func TestParseEvents(t *testing.T) {
+	suites := parseTestdata(t)
+	if !gospec.Len(t, suites, 1) {
+		return
+	}
+
+	suite := suites[0]
+	gospec.Equal(t, "example.com/pkg", suite.Package)
+	gospec.Equal(t, "fail", suite.Action)
+
+	total, failures, skipped := suite.Counts()
+	gospec.Equal(t, 3, total)
+	gospec.Equal(t, 1, failures)
+	gospec.Equal(t, 1, skipped)
+
+	tc := suite.Tests[0]
+	gospec.Equal(t, "TestFail", tc.Name)
+	if !gospec.Len(t, tc.Failures, 2) {
+		return
+	}
+
+	failure := tc.Failures[0]
+	gospec.Equal(t, "values of slice", failure.Message)
+	gospec.Equal(t, "Expect to be equal", failure.Error)
+	gospec.Equal(t, "z_test.go:10 pkg.TestFail", failure.Trace)
+	gospec.Equal(t, "--- Expected\n+++ Actual\n@@ -2,3 +2,3 @@\n  (int) 1,\n- (int) 2\n+ (int) 3\n }\n", failure.Diff)
+
+	failure = tc.Failures[1]
+	gospec.Equal(t, "", failure.Message)
+	gospec.Equal(t, "Expect to be true", failure.Error)
+	gospec.Equal(t, []gospec.FailureLabel{
+		{Label: "-expected", Content: "true"},
+		{Label: "+received", Content: "false"},
+		{Label: "Expression", Content: "gospec.True(t, false)"},
+	}, failure.Labels)
+}
+
+func TestParseEventsWithBuildFailure(t *testing.T) {
+	input := `{"Action":"output","Package":"example.com/broken","Output":"FAIL\texample.com/broken [build failed]\n"}
+{"Action":"fail","Package":"example.com/broken","Elapsed":0}
+`
+
+	suites, err := ParseEvents(strings.NewReader(input))
+	if !gospec.NotError(t, err) || !gospec.Len(t, suites, 1) || !gospec.Len(t, suites[0].Tests, 1) {
+		return
+	}
+
+	tc := suites[0].Tests[0]
+	gospec.Equal(t, packageCaseName, tc.Name)
+	gospec.True(t, tc.Failed())
+	gospec.Contains(t, tc.Failures[0].Error, "build failed")
+}
+
+func TestParseFailuresWithJSONRecord(t *testing.T) {
+	output := "    z_test.go:10: \n" +
+		"        \r\tError Trace:\tz_test.go:10 pkg.TestTrue\n" +
+		"        \r\tError:\tExpect to be true\n" +
+		"        \r\n" +
+		"    reporters.go:146: " + gospec.JSONReportPrefix + `{"error":"Expect to be true","expected":"true","actual":"false","trace":["z_test.go:10 pkg.TestTrue"]}` + "\n"
+
+	failures := ParseFailures(output)
+	if !gospec.Len(t, failures, 1) {
+		return
+	}
+
+	gospec.Equal(t, "Expect to be true", failures[0].Error)
+	gospec.Equal(t, "z_test.go:10 pkg.TestTrue", failures[0].Trace)
+}
+
+func TestWriteJUnit(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	if !gospec.NotError(t, WriteJUnit(buf, parseTestdata(t))) {
+		return
+	}
+
+	var report junitTestSuites
+	if !gospec.NotError(t, xml.Unmarshal(buf.Bytes(), &report)) || !gospec.Len(t, report.Suites, 1) {
+		return
+	}
+
+	suite := report.Suites[0]
+	gospec.Equal(t, 3, suite.Tests)
+	gospec.Equal(t, 1, suite.Failures)
+	gospec.Equal(t, 1, suite.Skipped)
+	gospec.Len(t, suite.Cases[0].Failures, 2)
+	gospec.Equal(t, "values of slice: Expect to be equal", suite.Cases[0].Failures[0].Message)
+	gospec.Contains(t, suite.Cases[0].Failures[0].Content, "+ (int) 3")
+	gospec.NotNil(t, suite.Cases[2].Skipped)
+}
+
+func TestWriteTAP(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	if !gospec.NotError(t, WriteTAP(buf, parseTestdata(t))) {
+		return
+	}
+
+	output := buf.String()
+	gospec.True(t, strings.HasPrefix(output, "TAP version 13\n1..3\n"))
+	gospec.Contains(t, output, "not ok 1 - example.com/pkg/TestFail\n")
+	gospec.Contains(t, output, "ok 2 - example.com/pkg/TestPass\n")
+	gospec.Contains(t, output, "ok 3 - example.com/pkg/TestSkip # SKIP\n")
+	gospec.Contains(t, output, "    - message: |2\n        values of slice: Expect to be equal\n")
+	gospec.Contains(t, output, "      diff: |2\n        --- Expected\n")
+}
+
+func TestWriteJUnitWithColors(t *testing.T) {
+	suites := []*Suite{
+		{
+			Package: "example.com/pkg",
+			Action:  "fail",
+			Tests: []*TestCase{
+				{
+					Package: "example.com/pkg",
+					Name:    "TestFail",
+					Action:  "fail",
+					Output:  "\x1b[1m\x1b[31m- (int) 2\x1b[0m\n",
+					Failures: []*Failure{
+						{
+							Error: "Expect to be equal",
+							Diff:  "\x1b[1m\x1b[31m- (int) 2\x1b[0m\n\x1b[1m\x1b[32m+ (int) 3\x1b[0m",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if !gospec.NotError(t, WriteJUnit(buf, suites)) {
+		return
+	}
+
+	gospec.NotContains(t, buf.String(), "\x1b")
+
+	var report junitTestSuites
+	if gospec.NotError(t, xml.Unmarshal(buf.Bytes(), &report)) && gospec.Len(t, report.Suites, 1) {
+		xcase := report.Suites[0].Cases[0]
+
+		gospec.Contains(t, xcase.Failures[0].Content, "- (int) 2\n+ (int) 3")
+		gospec.Equal(t, "- (int) 2\n", xcase.SystemOut.Content)
+	}
+}
+
+func TestWriteTAPWithoutSummary(t *testing.T) {
+	suites := []*Suite{
+		{
+			Package: "example.com/pkg",
+			Action:  "fail",
+			Tests: []*TestCase{
+				{
+					Package: "example.com/pkg",
+					Name:    "TestFail",
+					Action:  "fail",
+					Failures: []*Failure{
+						{
+							Trace: "z_test.go:10 pkg.TestFail",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if gospec.NotError(t, WriteTAP(buf, suites)) {
+		gospec.Contains(t, buf.String(), "  failures:\n    - message: \"\"\n      trace: |2\n        z_test.go:10 pkg.TestFail\n")
+	}
+}
+
+func TestWriteTAPWithColors(t *testing.T) {
+	suites := []*Suite{
+		{
+			Package: "example.com/pkg",
+			Action:  "fail",
+			Tests: []*TestCase{
+				{
+					Package: "example.com/pkg",
+					Name:    "TestFail",
+					Action:  "fail",
+					Failures: []*Failure{
+						{
+							Error: "Expect to be equal",
+							Diff:  "\x1b[1m\x1b[31m- (int) 2\x1b[0m\n\x1b[1m\x1b[32m+ (int) 3\x1b[0m",
+							Labels: []gospec.FailureLabel{
+								{Label: "-expected", Content: "  indented"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if !gospec.NotError(t, WriteTAP(buf, suites)) {
+		return
+	}
+
+	gospec.NotContains(t, buf.String(), "\x1b")
+	gospec.Contains(t, buf.String(), "      diff: |2\n        - (int) 2\n        + (int) 3\n")
+	gospec.Contains(t, buf.String(), "      expected: |2\n          indented\n")
+}
+
+func Test_runReportWithHelp(t *testing.T) {
+	gospec.NotError(t, runReport([]string{"-h"}))
+}
+
+func Test_tapKey(t *testing.T) {
+	keys := map[string]bool{
+		"message": true,
+	}
+
+	gospec.Equal(t, "expected", tapKey(keys, "-expected"))
+	gospec.Equal(t, "expected_2", tapKey(keys, "+expected"))
+	gospec.Equal(t, "expected_3", tapKey(keys, "expected"))
+	gospec.Equal(t, "message_2", tapKey(keys, "Message"))
+	gospec.Equal(t, "error_trace", tapKey(keys, "Error Trace"))
+	gospec.Equal(t, "label", tapKey(keys, "+"))
+	gospec.Equal(t, "label_2", tapKey(keys, "-:"))
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// WriteTAP writes suites as TAP version 13 to w.
+func WriteTAP(w io.Writer, suites []*Suite) error {
+	buf := new(strings.Builder)
+
+	total := 0
+	for _, suite := range suites {
+		total += len(suite.Tests)
+	}
+
+	buf.WriteString("TAP version 13\n")
+	fmt.Fprintf(buf, "1..%d\n", total)
+
+	n := 0
+	for _, suite := range suites {
+		for _, tc := range suite.Tests {
+			n++
+
+			name := tc.Package + "/" + tc.Name
+
+			switch {
+			case tc.Failed():
+				fmt.Fprintf(buf, "not ok %d - %s\n", n, name)
+
+				writeTAPFailures(buf, tc)
+
+			case tc.Skipped():
+				fmt.Fprintf(buf, "ok %d - %s # SKIP\n", n, name)
+
+			default:
+				fmt.Fprintf(buf, "ok %d - %s\n", n, name)
+
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+// writeTAPFailures writes failures of the test case as a YAML diagnostic block.
+func writeTAPFailures(buf *strings.Builder, tc *TestCase) {
+	buf.WriteString("  ---\n")
+	fmt.Fprintf(buf, "  duration_ms: %d\n", int64(tc.Elapsed*1000))
+
+	if len(tc.Failures) == 0 {
+		writeTAPBlock(buf, "  ", "output", tc.Output)
+		buf.WriteString("  ...\n")
+
+		return
+	}
+
+	buf.WriteString("  failures:\n")
+	for _, failure := range tc.Failures {
+		// the list item is always written, even if the failure has no summary
+		if summary := failure.Summary(); summary != "" {
+			writeTAPBlock(buf, "    - ", "message", summary)
+		} else {
+			buf.WriteString("    - message: \"\"\n")
+		}
+		writeTAPBlock(buf, "      ", "trace", failure.Trace)
+
+		keys := map[string]bool{
+			"message": true,
+			"trace":   true,
+			"diff":    true,
+		}
+		for _, label := range failure.Labels {
+			if label.Label == labelDiff {
+				continue
+			}
+
+			writeTAPBlock(buf, "      ", tapKey(keys, label.Label), label.Content)
+		}
+
+		writeTAPBlock(buf, "      ", "diff", failure.Diff)
+	}
+	buf.WriteString("  ...\n")
+}
+
+// tapKey returns a YAML key of the label, which is suffixed by a number if it's in keys already,
+// e.g. "expected_2" for "+expected" after "-expected".
+func tapKey(keys map[string]bool, label string) string {
+	key := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+			return unicode.ToLower(r)
+
+		case r == ' ', r == '_', r == '-':
+			return '_'
+
+		}
+
+		return -1
+	}, strings.Trim(label, "+-: "))
+	if key == "" {
+		key = "label"
+	}
+
+	unique := key
+	for i := 2; keys[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", key, i)
+	}
+	keys[unique] = true
+
+	return unique
+}
+
+// writeTAPBlock writes a YAML literal block of key with prefix. ANSI escape codes are removed,
+// since YAML does not allow control characters, and the block has an explicit indentation
+// indicator for values starting with spaces.
+func writeTAPBlock(buf *strings.Builder, prefix, key, value string) {
+	value = stripANSI(value)
+	if value == "" {
+		return
+	}
+
+	fmt.Fprintf(buf, "%s%s: |2\n", prefix, key)
+
+	indent := strings.Repeat(" ", len(prefix)+2)
+	for _, line := range strings.Split(strings.TrimRight(value, "\n"), "\n") {
+		buf.WriteString(indent + line + "\n")
+	}
+}
@@ -0,0 +1,669 @@
+package gospec
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// elementsOf returns elements of a slice or an array.
+func elementsOf(v interface{}) ([]interface{}, bool) {
+	rval := reflect.ValueOf(v)
+	if rval.Kind() != reflect.Slice && rval.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	elements := make([]interface{}, rval.Len())
+	for i := range elements {
+		elements[i] = rval.Index(i).Interface()
+	}
+
+	return elements, true
+}
+
+// matchElements pairs each element of expected with an equal element of actual, and returns
+// indexes of elements left unpaired in both.
+func matchElements(expected, actual []interface{}) (missing, extra []int) {
+	paired := make([]bool, len(actual))
+
+	for i, exp := range expected {
+		found := false
+		for j, act := range actual {
+			if !paired[j] && DeepEqual(exp, act) {
+				paired[j] = true
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			missing = append(missing, i)
+		}
+	}
+
+	for j := range actual {
+		if !paired[j] {
+			extra = append(extra, j)
+		}
+	}
+
+	return
+}
+
+// formatElements returns elements at indexes one per line prefixed by their indexes.
+func (c *Config) formatElements(elements []interface{}, indexes []int) string {
+	lines := make([]string, 0, len(indexes))
+	for _, i := range indexes {
+		s, _ := c.toString(elements[i], elements[i])
+
+		lines = append(lines, fmt.Sprintf("[%d] %s", i, s))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// assertElements checks all lists are slices or arrays, and returns their elements. The args are
+// params of the assertion called, which are shown in expressions of failures.
+func assertElements(t TestingT, extras []interface{}, args arguments, lists ...interface{}) ([][]interface{}, bool) {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	result := make([][]interface{}, 0, len(lists))
+
+	for _, list := range lists {
+		elements, ok := elementsOf(list)
+		if !ok {
+			_, acts := configOf(t).toString(nil, list)
+
+			return nil, Errorf(t, "Parameters must be slices or arrays", []labeledOutput{
+				{
+					label:   labelMessages,
+					content: formatExtras(extras...),
+				},
+				{
+					label:   "+received",
+					content: acts,
+				},
+			}, args)
+		}
+
+		result = append(result, elements)
+	}
+
+	return result, true
+}
+
+// ElementsMatch asserts that the specified lists contain the same elements regardless of order,
+// which means each element occurs as many times in both lists.
+//
+//	assert.ElementsMatch(t, []int{1, 3, 2, 3}, []int{3, 3, 1, 2})
+//
+// Returns whether the assertion was successful (true) or not (false).
+func ElementsMatch(t TestingT, expected, actual interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	lists, ok := assertElements(t, extras, arguments{
+		"expected": expected,
+		"actual":   actual,
+	}, expected, actual)
+	if !ok {
+		return false
+	}
+
+	missing, extra := matchElements(lists[0], lists[1])
+	if len(missing) > 0 || len(extra) > 0 {
+		c := configOf(t)
+
+		labels := []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+		}
+		if len(missing) > 0 {
+			labels = append(labels, labeledOutput{
+				label:   "-missing",
+				content: c.formatElements(lists[0], missing),
+			})
+		}
+		if len(extra) > 0 {
+			labels = append(labels, labeledOutput{
+				label:   "+extra",
+				content: c.formatElements(lists[1], extra),
+			})
+		}
+
+		return Errorf(t, "Expect to match elements regardless of order", labels, arguments{
+			"expected": expected,
+			"actual":   actual,
+		})
+	}
+
+	return true
+}
+
+// missingEntries returns keys of subset map whose values are absent or different in m.
+func missingEntries(m, subset reflect.Value) []reflect.Value {
+	var missing []reflect.Value
+
+	for _, key := range sortedKeys(subset) {
+		value := m.MapIndex(key)
+		if !value.IsValid() || !DeepEqual(subset.MapIndex(key).Interface(), value.Interface()) {
+			missing = append(missing, key)
+		}
+	}
+
+	return missing
+}
+
+// sortedKeys returns keys of the map sorted by their formats for stable output.
+//
+// NOTE: keys are formatted as reflect.Value, since keys of maps in unexported fields
+// cannot be converted by Interface().
+func sortedKeys(m reflect.Value) []reflect.Value {
+	keys := m.MapKeys()
+
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprintf("%#v", keys[i]) < fmt.Sprintf("%#v", keys[j])
+	})
+
+	return keys
+}
+
+// formatEntries returns entries of keys in m one per line.
+func (c *Config) formatEntries(m reflect.Value, keys []reflect.Value) string {
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		ks, _ := c.toString(key.Interface(), key.Interface())
+		vs, _ := c.toString(m.MapIndex(key).Interface(), m.MapIndex(key).Interface())
+
+		lines = append(lines, fmt.Sprintf("%s: %s", ks, vs))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// subsetOf returns missing elements of subset in list, which are both slices or arrays, or both maps
+// with keys of subset assignable to keys of list.
+func subsetOf(c *Config, list, subset interface{}) (missing string, ok bool, valid bool) {
+	lval, sval := reflect.ValueOf(list), reflect.ValueOf(subset)
+
+	if lval.Kind() == reflect.Map && sval.Kind() == reflect.Map {
+		if !sval.Type().Key().AssignableTo(lval.Type().Key()) {
+			return "", false, false
+		}
+
+		keys := missingEntries(lval, sval)
+
+		return c.formatEntries(sval, keys), len(keys) == 0, true
+	}
+
+	elements, lok := elementsOf(list)
+	subElements, sok := elementsOf(subset)
+	if !lok || !sok {
+		return "", false, false
+	}
+
+	var indexes []int
+	for i, element := range subElements {
+		found := false
+		for _, e := range elements {
+			if DeepEqual(element, e) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			indexes = append(indexes, i)
+		}
+	}
+
+	return c.formatElements(subElements, indexes), len(indexes) == 0, true
+}
+
+// Subset asserts that all elements of subset are contained in list, which are both slices or arrays,
+// or all entries of subset are contained in list if both of them are maps.
+//
+//	assert.Subset(t, []int{1, 2, 3}, []int{1, 3})
+//	assert.Subset(t, map[string]int{"a": 1, "b": 2}, map[string]int{"a": 1})
+//
+// Returns whether the assertion was successful (true) or not (false).
+func Subset(t TestingT, list, subset interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	missing, ok, valid := subsetOf(configOf(t), list, subset)
+	if !valid {
+		exps, acts := configOf(t).toString(list, subset)
+
+		return Errorf(t, "Parameters must be both slices or arrays, or both maps with assignable keys", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-list",
+				content: exps,
+			},
+			{
+				label:   "+subset",
+				content: acts,
+			},
+		}, arguments{
+			"list":   list,
+			"subset": subset,
+		})
+	}
+
+	if !ok {
+		exps, _ := configOf(t).toString(list, list)
+
+		return Errorf(t, "Expect to contain all elements of subset", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-list",
+				content: exps,
+			},
+			{
+				label:   "-missing",
+				content: missing,
+			},
+		}, arguments{
+			"list":   list,
+			"subset": subset,
+		})
+	}
+
+	return true
+}
+
+// NotSubset asserts that some elements of subset are NOT contained in list, which are both slices
+// or arrays, or some entries of subset are NOT contained in list if both of them are maps.
+//
+//	assert.NotSubset(t, []int{1, 2, 3}, []int{1, 4})
+//
+// Returns whether the assertion was successful (true) or not (false).
+func NotSubset(t TestingT, list, subset interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	_, ok, valid := subsetOf(configOf(t), list, subset)
+	if !valid {
+		exps, acts := configOf(t).toString(list, subset)
+
+		return Errorf(t, "Parameters must be both slices or arrays, or both maps with assignable keys", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-list",
+				content: exps,
+			},
+			{
+				label:   "+subset",
+				content: acts,
+			},
+		}, arguments{
+			"list":   list,
+			"subset": subset,
+		})
+	}
+
+	if ok {
+		exps, acts := configOf(t).toString(list, subset)
+
+		return Errorf(t, "Expect to NOT contain all elements of subset", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-list",
+				content: exps,
+			},
+			{
+				label:   "+subset",
+				content: acts,
+			},
+		}, arguments{
+			"list":   list,
+			"subset": subset,
+		})
+	}
+
+	return true
+}
+
+// ContainsAll asserts that the specified string, list(array, slice, channel...) or map contains all
+// elements, which is a slice or an array of substrings or elements.
+//
+//	assert.ContainsAll(t, "Hello World", []string{"Hello", "World"})
+//	assert.ContainsAll(t, []int{1, 2, 3}, []int{3, 1})
+//
+// NOTE: io.Reader values are searched once for all elements. Those neither exposing Bytes() nor seekable
+// are never consumed, but reported as failures, use ReaderContains for them instead.
+//
+// Returns whether the assertion was successful (true) or not (false).
+func ContainsAll(t TestingT, v, elements interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	lists, ok := assertElements(t, extras, arguments{
+		"v":        v,
+		"elements": elements,
+	}, elements)
+	if !ok {
+		return false
+	}
+
+	found, ok := containsAllOf(t, v, lists[0], extras, arguments{
+		"v":        v,
+		"elements": elements,
+	})
+	if !ok {
+		return false
+	}
+
+	var missing []int
+	for i := range lists[0] {
+		if !found[i] {
+			missing = append(missing, i)
+		}
+	}
+
+	if len(missing) > 0 {
+		c := configOf(t)
+		_, acts := c.toString(v, v)
+
+		return Errorf(t, "Expect to include all substrings or elements", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "+received",
+				content: acts,
+			},
+			{
+				label:   "-missing",
+				content: c.formatElements(lists[0], missing),
+			},
+		}, arguments{
+			"v":        v,
+			"elements": elements,
+		})
+	}
+
+	return true
+}
+
+// ContainsAny asserts that the specified string, list(array, slice, channel...) or map contains at
+// least one of elements, which is a slice or an array of substrings or elements.
+//
+//	assert.ContainsAny(t, "Hello World", []string{"Earth", "World"})
+//
+// NOTE: io.Reader values are searched once for all elements. Those neither exposing Bytes() nor seekable
+// are never consumed, but reported as failures, use ReaderContains for them instead.
+//
+// Returns whether the assertion was successful (true) or not (false).
+func ContainsAny(t TestingT, v, elements interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	lists, ok := assertElements(t, extras, arguments{
+		"v":        v,
+		"elements": elements,
+	}, elements)
+	if !ok {
+		return false
+	}
+
+	// none of no elements can be included
+	if len(lists[0]) == 0 {
+		_, acts := configOf(t).toString(v, v)
+
+		return Errorf(t, "Expect to include any of substrings or elements, but no elements are given", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "+received",
+				content: acts,
+			},
+		}, arguments{
+			"v":        v,
+			"elements": elements,
+		})
+	}
+
+	found, ok := containsAllOf(t, v, lists[0], extras, arguments{
+		"v":        v,
+		"elements": elements,
+	})
+	if !ok {
+		return false
+	}
+
+	missing := make([]int, 0, len(lists[0]))
+	for i := range lists[0] {
+		if found[i] {
+			return true
+		}
+
+		missing = append(missing, i)
+	}
+
+	c := configOf(t)
+	_, acts := c.toString(v, v)
+
+	return Errorf(t, "Expect to include any of substrings or elements", []labeledOutput{
+		{
+			label:   labelMessages,
+			content: formatExtras(extras...),
+		},
+		{
+			label:   "+received",
+			content: acts,
+		},
+		{
+			label:   "-missing",
+			content: c.formatElements(lists[0], missing),
+		},
+	}, arguments{
+		"v":        v,
+		"elements": elements,
+	})
+}
+
+// Unique asserts that the specified slice or array contains no duplicated elements.
+//
+//	assert.Unique(t, []string{"a", "b", "c"})
+//
+// Returns whether the assertion was successful (true) or not (false).
+func Unique(t TestingT, list interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	lists, ok := assertElements(t, extras, arguments{
+		"list": list,
+	}, list)
+	if !ok {
+		return false
+	}
+
+	elements := lists[0]
+
+	var duplicated []int
+	for i := 1; i < len(elements); i++ {
+		for j := 0; j < i; j++ {
+			if DeepEqual(elements[i], elements[j]) {
+				duplicated = append(duplicated, i)
+				break
+			}
+		}
+	}
+
+	if len(duplicated) > 0 {
+		return Errorf(t, "Expect to have no duplicated elements", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "+duplicated",
+				content: configOf(t).formatElements(elements, duplicated),
+			},
+		}, arguments{
+			"list": list,
+		})
+	}
+
+	return true
+}
+
+// ContainsN asserts that the specified slice or array contains the element exactly n times.
+//
+//	assert.ContainsN(t, []int{1, 2, 1}, 1, 2)
+//
+// Returns whether the assertion was successful (true) or not (false).
+func ContainsN(t TestingT, list, element interface{}, n int, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	lists, ok := assertElements(t, extras, arguments{
+		"list":    list,
+		"element": element,
+		"n":       n,
+	}, list)
+	if !ok {
+		return false
+	}
+
+	elements := lists[0]
+
+	var found []int
+	for i, e := range elements {
+		if DeepEqual(element, e) {
+			found = append(found, i)
+		}
+	}
+
+	if len(found) != n {
+		c := configOf(t)
+		exps, _ := c.toString(element, element)
+
+		labels := []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-element",
+				content: exps,
+			},
+		}
+		if len(found) > 0 {
+			labels = append(labels, labeledOutput{
+				label:   "+found",
+				content: c.formatElements(elements, found),
+			})
+		}
+
+		return Errorf(t, fmt.Sprintf("Expect to include the element %d time(s), but found %d time(s)", n, len(found)), labels, arguments{
+			"list":    list,
+			"element": element,
+			"n":       n,
+		})
+	}
+
+	return true
+}
+
+// ContainsInOrder asserts that the specified slice or array contains elements of sequence in the
+// same order, though not necessarily adjacent.
+//
+//	assert.ContainsInOrder(t, []string{"open", "read", "write", "close"}, []string{"open", "close"})
+//
+// Returns whether the assertion was successful (true) or not (false).
+func ContainsInOrder(t TestingT, list, sequence interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	lists, ok := assertElements(t, extras, arguments{
+		"list":     list,
+		"sequence": sequence,
+	}, list, sequence)
+	if !ok {
+		return false
+	}
+
+	elements, subsequence := lists[0], lists[1]
+
+	var matched []int
+	next := 0
+	for _, element := range subsequence {
+		found := false
+		for ; next < len(elements); next++ {
+			if DeepEqual(element, elements[next]) {
+				matched = append(matched, next)
+				found = true
+				next++
+				break
+			}
+		}
+
+		if !found {
+			break
+		}
+	}
+
+	if len(matched) < len(subsequence) {
+		c := configOf(t)
+
+		missing := make([]int, 0, len(subsequence)-len(matched))
+		for i := len(matched); i < len(subsequence); i++ {
+			missing = append(missing, i)
+		}
+
+		labels := []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+		}
+		if len(matched) > 0 {
+			labels = append(labels, labeledOutput{
+				label:   "+matched",
+				content: c.formatElements(elements, matched),
+			})
+		}
+		labels = append(labels, labeledOutput{
+			label:   "-missing",
+			content: c.formatElements(subsequence, missing),
+		})
+
+		return Errorf(t, fmt.Sprintf("Expect to include elements in order, but sequence[%d] is missing after matched ones", len(matched)), labels, arguments{
+			"list":     list,
+			"sequence": sequence,
+		})
+	}
+
+	return true
+}
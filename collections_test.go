@@ -0,0 +1,173 @@
+package gospec
+
+import (
+	"testing"
+)
+
+func TestElementsMatch(t *testing.T) {
+	mockT := new(testing.T)
+
+	trueCases := []struct {
+		expected, actual interface{}
+	}{
+		{[]int{}, []int{}},
+		{[]int{1, 3, 2, 3}, []int{3, 3, 1, 2}},
+		{[3]string{"a", "b", "c"}, []string{"c", "a", "b"}},
+		{[]interface{}{1, "a", nil}, []interface{}{nil, "a", 1}},
+	}
+
+	for i, tc := range trueCases {
+		True(t, ElementsMatch(mockT, tc.expected, tc.actual), "ElementsMatch should return true for trueCases(%d)", i)
+	}
+
+	falseCases := []struct {
+		expected, actual interface{}
+	}{
+		{[]int{1, 2}, []int{1, 2, 2}},
+		{[]int{1, 2, 2}, []int{1, 2}},
+		{[]int{1}, []int64{1}},
+		{[]int{1}, 1},
+		{nil, []int{}},
+	}
+
+	for i, fc := range falseCases {
+		False(t, ElementsMatch(mockT, fc.expected, fc.actual), "ElementsMatch should return false for falseCases(%d)", i)
+	}
+
+	rt := new(RecordingT)
+
+	False(t, ElementsMatch(rt, []string{"a", "b", "c"}, []string{"c", "d", "a", "e"}))
+
+	records := rt.Records()
+	if Len(t, records, 1) {
+		Equal(t, "[1] \"b\"", testingLabelOf(records[0], "-missing"))
+		Equal(t, "[1] \"d\"\n[3] \"e\"", testingLabelOf(records[0], "+extra"))
+	}
+
+	// empty labels are omitted
+	rt.Reset()
+
+	False(t, ElementsMatch(rt, []int{1, 2}, []int{1, 2, 2}))
+	False(t, ElementsMatch(rt, []int{1, 2, 2}, []int{1, 2}))
+
+	records = rt.Records()
+	if Len(t, records, 2) {
+		labels := []string{}
+		for _, record := range records {
+			for _, label := range record.Labels {
+				if label.Label != labelExpression {
+					labels = append(labels, label.Label)
+				}
+			}
+		}
+
+		Equal(t, []string{"+extra", "-missing"}, labels)
+	}
+}
+
+func TestSubset(t *testing.T) {
+	mockT := new(testing.T)
+
+	True(t, Subset(mockT, []int{1, 2, 3}, []int{1, 3}))
+	True(t, Subset(mockT, []int{1, 2, 3}, []int{}))
+	True(t, Subset(mockT, map[string]int{"a": 1, "b": 2}, map[string]int{"a": 1}))
+	False(t, Subset(mockT, []int{1, 2, 3}, []int{1, 4}))
+	False(t, Subset(mockT, map[string]int{"a": 1, "b": 2}, map[string]int{"a": 2}))
+	False(t, Subset(mockT, map[string]int{"a": 1}, []int{1}))
+	False(t, Subset(mockT, "abc", "a"))
+	False(t, Subset(mockT, map[string]int{"a": 1}, map[int]int{1: 1}))
+	True(t, Subset(mockT, map[interface{}]int{"a": 1}, map[string]int{"a": 1}))
+
+	True(t, NotSubset(mockT, []int{1, 2, 3}, []int{1, 4}))
+	True(t, NotSubset(mockT, map[string]int{"a": 1}, map[string]int{"b": 1}))
+	False(t, NotSubset(mockT, []int{1, 2, 3}, []int{3, 2}))
+	False(t, NotSubset(mockT, map[string]int{"a": 1, "b": 2}, map[string]int{"b": 2}))
+	False(t, NotSubset(mockT, 1, []int{1}))
+	False(t, NotSubset(mockT, map[string]int{"a": 1}, map[int]int{1: 1}))
+}
+
+func TestContainsAll(t *testing.T) {
+	mockT := new(testing.T)
+
+	True(t, ContainsAll(mockT, "Hello World", []string{"Hello", "World"}))
+	True(t, ContainsAll(mockT, []int{1, 2, 3}, []int{3, 1}))
+	True(t, ContainsAll(mockT, map[string]int{"a": 1, "b": 2}, []string{"a", "b"}))
+	False(t, ContainsAll(mockT, "Hello World", []string{"Hello", "Earth"}))
+	False(t, ContainsAll(mockT, []int{1, 2, 3}, 1))
+
+	True(t, ContainsAny(mockT, "Hello World", []string{"Earth", "World"}))
+	True(t, ContainsAny(mockT, []int{1, 2, 3}, []int{4, 3}))
+	False(t, ContainsAny(mockT, "Hello World", []string{"Earth", "Mars"}))
+	False(t, ContainsAny(mockT, []int{1, 2, 3}, []int{}))
+
+	rt := NewRecordingT("TestContainsAll")
+
+	False(t, ContainsAny(rt, []int{1, 2, 3}, []int{}))
+	if records := rt.Records(); Len(t, records, 1) {
+		Equal(t, "Expect to include any of substrings or elements, but no elements are given", records[0].Error)
+		Len(t, records[0].Labels, 2)
+		Equal(t, "+received", records[0].Labels[0].Label)
+		Equal(t, labelExpression, records[0].Labels[1].Label)
+	}
+}
+
+func TestUnique(t *testing.T) {
+	mockT := new(testing.T)
+
+	True(t, Unique(mockT, []int{}))
+	True(t, Unique(mockT, []string{"a", "b", "c"}))
+	True(t, Unique(mockT, []interface{}{1, int64(1), "1"}))
+	False(t, Unique(mockT, []string{"a", "b", "a"}))
+	False(t, Unique(mockT, [][]int{{1}, {1}}))
+	False(t, Unique(mockT, map[int]int{}))
+}
+
+func TestContainsN(t *testing.T) {
+	mockT := new(testing.T)
+
+	True(t, ContainsN(mockT, []int{1, 2, 1}, 1, 2))
+	True(t, ContainsN(mockT, []int{1, 2, 1}, 3, 0))
+	False(t, ContainsN(mockT, []int{1, 2, 1}, 1, 1))
+	False(t, ContainsN(mockT, []int{1, 2, 1}, 2, 2))
+	False(t, ContainsN(mockT, "aba", "a", 2))
+
+	rt := NewRecordingT("TestContainsN")
+
+	False(t, ContainsN(rt, []int{1, 2, 1}, 3, 1))
+	if records := rt.Records(); Len(t, records, 1) {
+		Len(t, records[0].Labels, 2)
+		Equal(t, "-element", records[0].Labels[0].Label)
+		Equal(t, labelExpression, records[0].Labels[1].Label)
+	}
+
+	// arguments of invalid params are described as well
+	rt.Reset()
+
+	list := "aba"
+	False(t, ContainsN(rt, list, "a", 2))
+	Contains(t, rt.String(), `list → "aba"`)
+}
+
+func TestContainsInOrder(t *testing.T) {
+	mockT := new(testing.T)
+
+	list := []string{"open", "read", "write", "close"}
+
+	True(t, ContainsInOrder(mockT, list, []string{"open", "close"}))
+	True(t, ContainsInOrder(mockT, list, []string{"read", "write"}))
+	True(t, ContainsInOrder(mockT, list, []string{}))
+	False(t, ContainsInOrder(mockT, list, []string{"close", "open"}))
+	False(t, ContainsInOrder(mockT, list, []string{"open", "open"}))
+	False(t, ContainsInOrder(mockT, list, []string{"seek"}))
+	False(t, ContainsInOrder(mockT, list, "open"))
+
+	// empty labels are omitted
+	rt := new(RecordingT)
+
+	False(t, ContainsInOrder(rt, list, []string{"seek"}))
+	if Len(t, rt.Records(), 1) {
+		for _, label := range rt.Records()[0].Labels {
+			NotEqual(t, "+matched", label.Label)
+		}
+	}
+}
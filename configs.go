@@ -0,0 +1,267 @@
+package gospec
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// Config defines output and behaviour of assertions.
+//
+// The global config is initialized from DefaultConfig, and can be changed by SetConfig or UpdateConfig.
+// Use WithConfig for changing the config of assertions of a TestingT only.
+type Config struct {
+	// Color defines when failure output is colorized, env GOSPEC_COLOR=auto|always|never.
+	Color ColorMode
+	// DiffStyle defines the layout of diffs, env GOSPEC_DIFF_STYLE=unified|side-by-side.
+	DiffStyle DiffStyle
+	// DiffContext is the number of context lines around changes of diffs, env GOSPEC_DIFF_CONTEXT.
+	DiffContext int
+	// Whitespaces defines which invisible characters are made visible in diffs of text,
+	// env GOSPEC_WHITESPACES=none|crlf|all.
+	Whitespaces WhitespaceMode
+	// WordDiff enables word-level diffs of single-line strings, env GOSPEC_WORD_DIFF.
+	WordDiff bool
+	// HexWindow is the number of unchanged rows around differences of hex dump diffs, env GOSPEC_HEX_WINDOW.
+	HexWindow int
+	// Limits defines limits of values and diffs, env GOSPEC_MAX_DEPTH, GOSPEC_MAX_ELEMENTS,
+	// GOSPEC_MAX_STRING_LEN, GOSPEC_MAX_DIFF_LINES and GOSPEC_ARTIFACTS_DIR.
+	Limits Limits
+	// TraceDepth limits the number of frames of error trace, zero means no limit, env GOSPEC_TRACE_DEPTH.
+	TraceDepth int
+	// TraceSkipDirs are names of dirs whose frames are hidden from error trace, except frames of test files.
+	TraceSkipDirs []string
+	// TraceSkipFuncs are patterns of funcs whose frames are hidden from error trace, see RegisterHelper.
+	TraceSkipFuncs []string
+	// PointerAddresses enables addresses of pointers in diffs, env GOSPEC_POINTER_ADDRESSES.
+	PointerAddresses bool
+	// SortMapKeys sorts keys of maps in diffs for stable output, env GOSPEC_SORT_MAP_KEYS.
+	SortMapKeys bool
+	// FloatPolicy defines how NaN and infinities are compared by float assertions,
+	// env GOSPEC_FLOAT_POLICY=nan-equal,inf-unequal.
+	FloatPolicy FloatPolicy
+	// Seed seeds math/rand sources of statistical assertions, zero means seeding by current time,
+	// env GOSPEC_SEED.
+	Seed int64
+	// Stringers formats values implementing fmt.GoStringer or fmt.Stringer by their methods
+	// unless a formatter is registered for them by RegisterFormatter, env GOSPEC_STRINGERS.
+	Stringers bool
+	// Expressions shows the source of failed assertion calls with values of their arguments,
+	// env GOSPEC_EXPRESSIONS.
+	Expressions bool
+	// FailNow stops the test by FailNow of TestingT after failures if it's supported, see Require.
+	FailNow bool
+	// NewLine is the line break of failure output. The default "\n\r\t" erases the padding added by testing.T.
+	NewLine string
+}
+
+var (
+	configMux sync.RWMutex
+	config    = DefaultConfig()
+)
+
+// DefaultConfig returns the default config overridden by GOSPEC_* env vars.
+func DefaultConfig() Config {
+	c := Config{
+		Color:         ColorAuto,
+		DiffStyle:     DiffUnified,
+		DiffContext:   1,
+		Whitespaces:   ShowCRLF,
+		HexWindow:     1,
+		TraceSkipDirs: []string{"assert", "mock", "require"},
+		SortMapKeys:   true,
+		Expressions:   true,
+		NewLine:       labelNewLine,
+		Limits: Limits{
+			MaxDepth:     10,
+			MaxElements:  100,
+			MaxStringLen: 8192,
+			MaxDiffLines: 300,
+		},
+	}
+
+	switch strings.ToLower(os.Getenv("GOSPEC_COLOR")) {
+	case "always":
+		c.Color = ColorAlways
+
+	case "never":
+		c.Color = ColorNever
+
+	}
+
+	switch strings.ToLower(os.Getenv("GOSPEC_DIFF_STYLE")) {
+	case "side-by-side", "sidebyside":
+		c.DiffStyle = DiffSideBySide
+
+	case "unified":
+		c.DiffStyle = DiffUnified
+
+	}
+
+	switch strings.ToLower(os.Getenv("GOSPEC_WHITESPACES")) {
+	case "none":
+		c.Whitespaces = ShowNoWhitespaces
+
+	case "crlf":
+		c.Whitespaces = ShowCRLF
+
+	case "all":
+		c.Whitespaces = ShowAllWhitespaces
+
+	}
+
+	for _, policy := range strings.Split(strings.ToLower(os.Getenv("GOSPEC_FLOAT_POLICY")), ",") {
+		switch strings.TrimSpace(policy) {
+		case "nan-equal":
+			c.FloatPolicy |= FloatNaNEqual
+
+		case "inf-unequal":
+			c.FloatPolicy |= FloatInfUnequal
+
+		}
+	}
+
+	lookupEnvInt("GOSPEC_DIFF_CONTEXT", &c.DiffContext)
+	lookupEnvInt("GOSPEC_HEX_WINDOW", &c.HexWindow)
+	lookupEnvInt("GOSPEC_TRACE_DEPTH", &c.TraceDepth)
+	lookupEnvInt("GOSPEC_MAX_DEPTH", &c.Limits.MaxDepth)
+	lookupEnvInt("GOSPEC_MAX_ELEMENTS", &c.Limits.MaxElements)
+	lookupEnvInt("GOSPEC_MAX_STRING_LEN", &c.Limits.MaxStringLen)
+	lookupEnvInt("GOSPEC_MAX_DIFF_LINES", &c.Limits.MaxDiffLines)
+	if seed, err := strconv.ParseInt(os.Getenv("GOSPEC_SEED"), 10, 64); err == nil {
+		c.Seed = seed
+	}
+
+	lookupEnvBool("GOSPEC_WORD_DIFF", &c.WordDiff)
+	lookupEnvBool("GOSPEC_POINTER_ADDRESSES", &c.PointerAddresses)
+	lookupEnvBool("GOSPEC_SORT_MAP_KEYS", &c.SortMapKeys)
+	lookupEnvBool("GOSPEC_STRINGERS", &c.Stringers)
+	lookupEnvBool("GOSPEC_EXPRESSIONS", &c.Expressions)
+
+	if dir := os.Getenv("GOSPEC_ARTIFACTS_DIR"); dir != "" {
+		c.Limits.ArtifactDir = dir
+	}
+
+	return c
+}
+
+// SetConfig replaces the global config.
+func SetConfig(c Config) {
+	configMux.Lock()
+	config = c.clone()
+	configMux.Unlock()
+}
+
+// GetConfig returns a copy of the global config.
+func GetConfig() Config {
+	configMux.RLock()
+	defer configMux.RUnlock()
+
+	return config.clone()
+}
+
+// UpdateConfig changes the global config by fn atomically.
+func UpdateConfig(fn func(c *Config)) {
+	configMux.Lock()
+	defer configMux.Unlock()
+
+	c := config.clone()
+	fn(&c)
+
+	config = c
+}
+
+// RegisterHelper hides frames of funcs matching patterns from error trace of failures, which are
+// package paths, func names or globs of path.Match, see TraceSkipFuncs of Config.
+//
+//	gospec.RegisterHelper("github.com/acme/testutil", "github.com/acme/app.assertUser")
+func RegisterHelper(patterns ...string) {
+	UpdateConfig(func(c *Config) {
+		c.TraceSkipFuncs = append(c.TraceSkipFuncs, patterns...)
+	})
+}
+
+// configuredT is a TestingT with its own config.
+type configuredT struct {
+	TestingT
+
+	config Config
+}
+
+// WithConfig returns a TestingT applying a config to assertions with it instead of the global config,
+// which is the config of t changed by fns. Settings not changed by fns are kept.
+//
+//	assert := gospec.WithConfig(t, func(c *gospec.Config) {
+//		c.DiffContext = 3
+//	})
+//	gospec.Equal(assert, expected, actual)
+func WithConfig(t TestingT, fns ...func(c *Config)) TestingT {
+	c := configOf(t).clone()
+	for _, fn := range fns {
+		fn(&c)
+	}
+
+	if ct, ok := t.(*configuredT); ok {
+		t = ct.TestingT
+	}
+
+	return &configuredT{
+		TestingT: t,
+		config:   c,
+	}
+}
+
+// configOf returns the config applying to assertions with t.
+func configOf(t TestingT) *Config {
+	if ct, ok := t.(*configuredT); ok {
+		return &ct.config
+	}
+
+	c := GetConfig()
+	return &c
+}
+
+func (c Config) clone() Config {
+	if c.TraceSkipFuncs != nil {
+		c.TraceSkipFuncs = append([]string{}, c.TraceSkipFuncs...)
+	}
+	if c.TraceSkipDirs != nil {
+		c.TraceSkipDirs = append([]string{}, c.TraceSkipDirs...)
+	}
+
+	return c
+}
+
+// newLine returns the line break of failure output.
+func (c *Config) newLine() string {
+	if c.NewLine == "" {
+		return labelNewLine
+	}
+
+	return c.NewLine
+}
+
+// spew returns a spew config for dumping values of diffs.
+func (c *Config) spew() *spew.ConfigState {
+	return &spew.ConfigState{
+		Indent:                  " ",
+		DisablePointerAddresses: !c.PointerAddresses,
+		DisableCapacities:       true,
+		SortKeys:                c.SortMapKeys,
+	}
+}
+
+func lookupEnvInt(key string, value *int) {
+	if n, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		*value = n
+	}
+}
+
+func lookupEnvBool(key string, value *bool) {
+	if b, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		*value = b
+	}
+}
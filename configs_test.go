@@ -0,0 +1,77 @@
+package gospec
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDefaultConfig(t *testing.T) {
+	c := DefaultConfig()
+	Equal(t, 1, c.DiffContext)
+	Equal(t, ShowCRLF, c.Whitespaces)
+	Equal(t, []string{"assert", "mock", "require"}, c.TraceSkipDirs)
+	True(t, c.SortMapKeys)
+	False(t, c.PointerAddresses)
+
+	os.Setenv("GOSPEC_DIFF_CONTEXT", "3")
+	os.Setenv("GOSPEC_DIFF_STYLE", "side-by-side")
+	os.Setenv("GOSPEC_WORD_DIFF", "true")
+	os.Setenv("GOSPEC_MAX_ELEMENTS", "invalid")
+	defer func() {
+		os.Unsetenv("GOSPEC_DIFF_CONTEXT")
+		os.Unsetenv("GOSPEC_DIFF_STYLE")
+		os.Unsetenv("GOSPEC_WORD_DIFF")
+		os.Unsetenv("GOSPEC_MAX_ELEMENTS")
+	}()
+
+	c = DefaultConfig()
+	Equal(t, 3, c.DiffContext)
+	Equal(t, DiffSideBySide, c.DiffStyle)
+	True(t, c.WordDiff)
+	Equal(t, 100, c.Limits.MaxElements)
+}
+
+func TestUpdateConfig(t *testing.T) {
+	origin := GetConfig()
+	defer SetConfig(origin)
+
+	UpdateConfig(func(c *Config) {
+		c.DiffContext = 0
+		c.TraceSkipDirs = append(c.TraceSkipDirs, "helpers")
+	})
+
+	Equal(t, 0, GetConfig().DiffContext)
+	Equal(t, []string{"assert", "mock", "require", "helpers"}, GetConfig().TraceSkipDirs)
+	Equal(t, []string{"assert", "mock", "require"}, origin.TraceSkipDirs)
+}
+
+func TestWithConfig(t *testing.T) {
+	mockT := new(testingLogger)
+
+	c := GetConfig()
+	c.DiffContext = 0
+	c.Color = ColorNever
+	c.NewLine = "\n"
+
+	assert := WithConfig(mockT, func(c *Config) {
+		c.DiffContext = 0
+		c.Color = ColorNever
+		c.NewLine = "\n"
+	})
+	Equal(t, &c, configOf(assert))
+	Equal(t, 1, configOf(mockT).DiffContext)
+
+	// applying a config again changes the previous one
+	again := WithConfig(assert, func(c *Config) {
+		c.DiffContext = 2
+	})
+	True(t, again.(*configuredT).TestingT == mockT)
+	Equal(t, 2, configOf(again).DiffContext)
+	Equal(t, ColorNever, configOf(again).Color)
+	Equal(t, 0, configOf(assert).DiffContext)
+
+	output := &testingOutput{config: configOf(again)}
+	output.Add(labeledOutput{label: labelDiff, content: configOf(assert).diff([]int{1, 2, 3}, []int{1, 5, 3})})
+	True(t, strings.Contains(output.String(), "\n \t@@ -3 +3 @@\n"))
+}
@@ -0,0 +1,367 @@
+package gospec
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// WhitespaceMode defines which invisible characters are made visible in diffs of text.
+type WhitespaceMode int
+
+// Whitespace modes
+const (
+	// ShowCRLF shows carriage returns as ␍, which would garble the output otherwise.
+	ShowCRLF WhitespaceMode = 1 << iota
+	// ShowTabs shows tabs as →.
+	ShowTabs
+	// ShowTrailingSpaces shows spaces at the end of lines as ·.
+	ShowTrailingSpaces
+
+	ShowNoWhitespaces  WhitespaceMode = 0
+	ShowAllWhitespaces                = ShowCRLF | ShowTabs | ShowTrailingSpaces
+)
+
+var (
+	// rxWords splits text into words, runs of whitespaces and punctuations
+	rxWords = regexp.MustCompile(`\w+|\s+|[^\w\s]`)
+)
+
+// SetWhitespaceMode changes which invisible characters are made visible in diffs of text.
+func SetWhitespaceMode(mode WhitespaceMode) {
+	UpdateConfig(func(c *Config) {
+		c.Whitespaces = mode
+	})
+}
+
+// SetWordDiff enables or disables word-level diffs of single-line strings.
+func SetWordDiff(enabled bool) {
+	UpdateConfig(func(c *Config) {
+		c.WordDiff = enabled
+	})
+}
+
+// toText returns contents of expected and actual if both of them are strings or []byte holding
+// UTF-8 text, which are diffed as text instead of spew dumps.
+func toText(expected, actual interface{}) (exps, acts string, ok bool) {
+	exps, ok = textOf(expected)
+	if !ok {
+		return
+	}
+
+	acts, ok = textOf(actual)
+	return
+}
+
+func textOf(v interface{}) (string, bool) {
+	rval := reflect.ValueOf(v)
+	if rval.Kind() == reflect.Ptr {
+		if rval.IsNil() {
+			return "", false
+		}
+
+		rval = rval.Elem()
+	}
+
+	switch rval.Kind() {
+	case reflect.String:
+		return rval.String(), true
+
+	case reflect.Slice:
+		if rval.Type().Elem().Kind() != reflect.Uint8 {
+			return "", false
+		}
+
+		data := rval.Bytes()
+		if !utf8.Valid(data) {
+			return "", false
+		}
+
+		return string(data), true
+	}
+
+	return "", false
+}
+
+// diffText returns a unified diff of texts line by line, or word by word for single-line texts
+// if word diff is enabled. It returns false if the texts should be diffed by spew dumps.
+func diffText(expected, actual string, c *Config) (string, bool) {
+	mode := c.Whitespaces
+
+	if !strings.Contains(expected, "\n") && !strings.Contains(actual, "\n") {
+		if !c.WordDiff {
+			return "", false
+		}
+
+		return diffWords(expected, actual, mode), true
+	}
+
+	exps := difflib.SplitLines(expected)
+	for i, line := range exps {
+		exps[i] = visualizeWhitespaces(line, mode)
+	}
+
+	acts := difflib.SplitLines(actual)
+	for i, line := range acts {
+		acts[i] = visualizeWhitespaces(line, mode)
+	}
+
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        exps,
+		B:        acts,
+		FromFile: "Expected",
+		FromDate: "",
+		ToFile:   "Actual",
+		ToDate:   "",
+		Context:  c.DiffContext,
+	})
+
+	return diff, true
+}
+
+// diffWords returns a diff of single-line texts with removed words marked as [-word-]
+// and added words marked as {+word+}.
+func diffWords(expected, actual string, mode WhitespaceMode) string {
+	exps := rxWords.FindAllString(expected, -1)
+	acts := rxWords.FindAllString(actual, -1)
+
+	buf := new(strings.Builder)
+	buf.WriteString("--- Expected\n+++ Actual\n@@ words @@\n ")
+
+	matcher := difflib.NewMatcherWithJunk(exps, acts, false, nil)
+	for _, op := range matcher.GetOpCodes() {
+		removed := strings.Join(exps[op.I1:op.I2], "")
+		added := strings.Join(acts[op.J1:op.J2], "")
+
+		switch op.Tag {
+		case 'e':
+			buf.WriteString(visualizeWhitespaces(removed, mode&^ShowTrailingSpaces))
+
+		case 'd':
+			buf.WriteString("[-" + visualizeWhitespaces(removed, mode|ShowTrailingSpaces) + "-]")
+
+		case 'i':
+			buf.WriteString("{+" + visualizeWhitespaces(added, mode|ShowTrailingSpaces) + "+}")
+
+		case 'r':
+			buf.WriteString("[-" + visualizeWhitespaces(removed, mode|ShowTrailingSpaces) + "-]")
+			buf.WriteString("{+" + visualizeWhitespaces(added, mode|ShowTrailingSpaces) + "+}")
+
+		}
+	}
+
+	buf.WriteString("\n")
+
+	return buf.String()
+}
+
+// visualizeWhitespaces replaces invisible characters of the line by mode. The trailing "\n"
+// of the line, if any, is kept as is.
+func visualizeWhitespaces(line string, mode WhitespaceMode) string {
+	newline := ""
+	if strings.HasSuffix(line, "\n") {
+		line = line[:len(line)-1]
+		newline = "\n"
+	}
+
+	if mode&ShowTrailingSpaces != 0 {
+		trimmed := strings.TrimRight(line, " \r")
+		trailing := line[len(trimmed):]
+
+		line = trimmed + strings.Replace(trailing, " ", "·", -1)
+	}
+
+	if mode&ShowTabs != 0 {
+		line = strings.Replace(line, "\t", "→", -1)
+	}
+
+	if mode&ShowCRLF != 0 {
+		line = strings.Replace(line, "\r", "␍", -1)
+	}
+
+	return line + newline
+}
+
+const hexRowSize = 16
+
+// SetHexWindow changes the number of unchanged rows shown around differences of hex dump diffs.
+func SetHexWindow(rows int) {
+	if rows < 0 {
+		rows = 0
+	}
+
+	UpdateConfig(func(c *Config) {
+		c.HexWindow = rows
+	})
+}
+
+// bytesOf returns the content of v if it's a []byte or a pointer to []byte.
+func bytesOf(v interface{}) ([]byte, bool) {
+	rval := reflect.ValueOf(v)
+	if rval.Kind() == reflect.Ptr {
+		if rval.IsNil() {
+			return nil, false
+		}
+
+		rval = rval.Elem()
+	}
+
+	if rval.Kind() != reflect.Slice || rval.Type().Elem().Kind() != reflect.Uint8 {
+		return nil, false
+	}
+
+	return rval.Bytes(), true
+}
+
+// byteRange represents a range of differing bytes, [start, end).
+type byteRange struct {
+	start, end int
+}
+
+// diffByteRanges returns ranges of bytes differing between expected and actual, including
+// the tail of the longer one.
+func diffByteRanges(expected, actual []byte) (ranges []byteRange) {
+	n := len(expected)
+	if len(actual) < n {
+		n = len(actual)
+	}
+
+	for i := 0; i < n; i++ {
+		if expected[i] == actual[i] {
+			continue
+		}
+
+		if last := len(ranges) - 1; last >= 0 && ranges[last].end == i {
+			ranges[last].end = i + 1
+			continue
+		}
+
+		ranges = append(ranges, byteRange{start: i, end: i + 1})
+	}
+
+	if len(expected) != len(actual) {
+		end := len(expected)
+		if len(actual) > end {
+			end = len(actual)
+		}
+
+		if last := len(ranges) - 1; last >= 0 && ranges[last].end == n {
+			ranges[last].end = end
+		} else {
+			ranges = append(ranges, byteRange{start: n, end: end})
+		}
+	}
+
+	return
+}
+
+// diffBytes returns a xxd-style hex dump diff of binary data, which lists offsets and lengths of
+// all differing byte ranges, and shows rows of differences with window unchanged rows around.
+func diffBytes(expected, actual []byte, window int) string {
+	ranges := diffByteRanges(expected, actual)
+	if len(ranges) == 0 {
+		return ""
+	}
+
+	buf := new(strings.Builder)
+	fmt.Fprintf(buf, "--- Expected (len=%d)\n+++ Actual (len=%d)\n", len(expected), len(actual))
+	fmt.Fprintf(buf, "differences: %d range(s), first at offset 0x%08x\n", len(ranges), ranges[0].start)
+
+	for i, r := range ranges {
+		if i >= 20 {
+			fmt.Fprintf(buf, "  ... %d more range(s)\n", len(ranges)-i)
+			break
+		}
+
+		fmt.Fprintf(buf, "  0x%08x-0x%08x (%d byte(s))\n", r.start, r.end-1, r.end-r.start)
+	}
+
+	size := len(expected)
+	if len(actual) > size {
+		size = len(actual)
+	}
+	rows := (size + hexRowSize - 1) / hexRowSize
+
+	// mark rows to show, which are differing rows and rows within the window around them
+	shown := make([]bool, rows)
+	for _, r := range ranges {
+		first := r.start/hexRowSize - window
+		last := (r.end-1)/hexRowSize + window
+
+		for row := first; row <= last; row++ {
+			if row >= 0 && row < rows {
+				shown[row] = true
+			}
+		}
+	}
+
+	for row := 0; row < rows; row++ {
+		if !shown[row] {
+			continue
+		}
+
+		if row == 0 || !shown[row-1] {
+			fmt.Fprintf(buf, "@@ 0x%08x @@\n", row*hexRowSize)
+		}
+
+		exps := hexDumpRow(expected, row)
+		acts := hexDumpRow(actual, row)
+		if exps == acts {
+			buf.WriteString(" " + exps + "\n")
+			continue
+		}
+
+		if exps != "" {
+			buf.WriteString("-" + exps + "\n")
+		}
+		if acts != "" {
+			buf.WriteString("+" + acts + "\n")
+		}
+	}
+
+	return buf.String()
+}
+
+// hexDumpRow returns the row of data formatted as xxd does, or an empty string if the row
+// is out of data.
+func hexDumpRow(data []byte, row int) string {
+	offset := row * hexRowSize
+	if offset >= len(data) {
+		return ""
+	}
+
+	end := offset + hexRowSize
+	if end > len(data) {
+		end = len(data)
+	}
+
+	buf := new(strings.Builder)
+	fmt.Fprintf(buf, "%08x: ", offset)
+
+	for i := 0; i < hexRowSize; i++ {
+		if offset+i < end {
+			fmt.Fprintf(buf, "%02x", data[offset+i])
+		} else {
+			buf.WriteString("  ")
+		}
+
+		if i%2 == 1 {
+			buf.WriteString(" ")
+		}
+	}
+
+	buf.WriteString(" ")
+	for _, b := range data[offset:end] {
+		if b < 0x20 || b > 0x7e {
+			b = '.'
+		}
+
+		buf.WriteByte(b)
+	}
+
+	return buf.String()
+}
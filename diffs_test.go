@@ -0,0 +1,109 @@
+package gospec
+
+import (
+	"testing"
+)
+
+func Test_diffWithMultilineText(t *testing.T) {
+	expected := `--- Expected
++++ Actual
+@@ -1,3 +1,3 @@
+ Hello,
+-world
++gospec
+ !
+`
+	Equal(t, expected, diff("Hello,\nworld\n!", "Hello,\ngospec\n!"))
+	Equal(t, expected, diff([]byte("Hello,\nworld\n!"), []byte("Hello,\ngospec\n!")))
+
+	expected = `--- Expected
++++ Actual
+@@ -1,2 +1,2 @@
+-Hello,␍
++Hello,
+ world
+`
+	Equal(t, expected, diff("Hello,\r\nworld", "Hello,\nworld"))
+
+	Equal(t, "", diff("Hello,\nworld", "Hello,\nworld"))
+
+	// binary data is diffed by hex dump
+	NotContains(t, diff([]byte("\xff\n"), []byte("\xfe\n")), "@@ -1 +1 @@")
+}
+
+func Test_diffBytes(t *testing.T) {
+	expected := make([]byte, 80)
+	for i := range expected {
+		expected[i] = byte('A' + i%26)
+	}
+
+	actual := append([]byte{}, expected...)
+	actual[3] = 0x00
+	actual[4] = 0xff
+	actual[70] = '\n'
+	actual = append(actual, 0x01, 0x02)
+
+	result := `--- Expected (len=80)
++++ Actual (len=82)
+differences: 3 range(s), first at offset 0x00000003
+  0x00000003-0x00000004 (2 byte(s))
+  0x00000046-0x00000046 (1 byte(s))
+  0x00000050-0x00000051 (2 byte(s))
+@@ 0x00000000 @@
+-00000000: 4142 4344 4546 4748 494a 4b4c 4d4e 4f50  ABCDEFGHIJKLMNOP
++00000000: 4142 4300 ff46 4748 494a 4b4c 4d4e 4f50  ABC..FGHIJKLMNOP
+ 00000010: 5152 5354 5556 5758 595a 4142 4344 4546  QRSTUVWXYZABCDEF
+@@ 0x00000030 @@
+ 00000030: 5758 595a 4142 4344 4546 4748 494a 4b4c  WXYZABCDEFGHIJKL
+-00000040: 4d4e 4f50 5152 5354 5556 5758 595a 4142  MNOPQRSTUVWXYZAB
++00000040: 4d4e 4f50 5152 0a54 5556 5758 595a 4142  MNOPQR.TUVWXYZAB
++00000050: 0102                                     ..
+`
+	Equal(t, result, diffBytes(expected, actual, 1))
+	Equal(t, "", diffBytes(expected, expected, 1))
+
+	SetHexWindow(0)
+	defer SetHexWindow(1)
+
+	result = `--- Expected (len=2)
++++ Actual (len=1)
+differences: 1 range(s), first at offset 0x00000001
+  0x00000001-0x00000001 (1 byte(s))
+@@ 0x00000000 @@
+-00000000: ff02                                     ..
++00000000: ff                                       .
+`
+	Equal(t, result, diff([]byte{0xff, 0x02}, []byte{0xff}))
+}
+
+func Test_diffWithWhitespaceMode(t *testing.T) {
+	SetWhitespaceMode(ShowAllWhitespaces)
+	defer SetWhitespaceMode(ShowCRLF)
+
+	expected := `--- Expected
++++ Actual
+@@ -1,2 +1,2 @@
+-→Hello,··
++→Hello,
+ world
+`
+	Equal(t, expected, diff("\tHello,  \nworld", "\tHello,\nworld"))
+}
+
+func Test_diffWithWordDiff(t *testing.T) {
+	SetWordDiff(true)
+	defer SetWordDiff(false)
+
+	expected := `--- Expected
++++ Actual
+@@ words @@
+ Hello, [-world-]{+gospec+}![-·-]
+`
+	Equal(t, expected, diff("Hello, world! ", "Hello, gospec!"))
+}
+
+func Test_visualizeWhitespaces(t *testing.T) {
+	Equal(t, "a\tb  \n", visualizeWhitespaces("a\tb  \n", ShowNoWhitespaces))
+	Equal(t, "a→b··␍\n", visualizeWhitespaces("a\tb  \r\n", ShowAllWhitespaces))
+	Equal(t, "a b␍", visualizeWhitespaces("a b\r", ShowCRLF))
+}
@@ -0,0 +1,272 @@
+package gospec
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+const labelExpression = "Expression"
+
+// sourceFile is a parsed go source file.
+type sourceFile struct {
+	fset *token.FileSet
+	file *ast.File
+}
+
+var (
+	sourceMux   sync.Mutex
+	sourceFiles = map[string]*sourceFile{}
+)
+
+// parseSource returns the parsed file of filename, which is cached. It returns nil if the source
+// is unavailable, e.g. tests are run by a binary built elsewhere.
+func parseSource(filename string) *sourceFile {
+	sourceMux.Lock()
+	defer sourceMux.Unlock()
+
+	if source, ok := sourceFiles[filename]; ok {
+		return source
+	}
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, filename, nil, 0)
+	if err != nil {
+		sourceFiles[filename] = nil
+		return nil
+	}
+
+	source := &sourceFile{
+		fset: fset,
+		file: file,
+	}
+	sourceFiles[filename] = source
+
+	return source
+}
+
+// getCallSite returns the frame of the outermost exported func of the package in the call stack,
+// which is the assertion invoked, and the frame calling it.
+func getCallSite() (assertion, caller runtime.Frame, ok bool) {
+	pkgPath := reflect.TypeOf(labeledOutput{}).PkgPath()
+
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	found := false
+	for {
+		frame, more := frames.Next()
+
+		if found {
+			caller, ok = frame, true
+			found = false
+		}
+
+		if !strings.HasSuffix(frame.File, "_test.go") && strings.HasPrefix(frame.Function, pkgPath+".") {
+			fn := strings.TrimPrefix(frame.Function, pkgPath+".")
+
+			segments := strings.Split(fn, ".")
+			rune, _ := utf8.DecodeRuneInString(segments[len(segments)-1])
+			if unicode.IsUpper(rune) {
+				assertion, ok, found = frame, false, true
+			}
+		}
+
+		// Drop the package
+		segments := strings.Split(frame.Function, ".")
+		fn := segments[len(segments)-1]
+		if !more || fn == "tRunner" ||
+			isTest(fn, "Test") ||
+			isTest(fn, "Benchmark") ||
+			isTest(fn, "Example") {
+			break
+		}
+	}
+
+	return
+}
+
+// findCall returns the innermost call of name at line of the source.
+func (source *sourceFile) findCall(name string, line int) *ast.CallExpr {
+	var call *ast.CallExpr
+
+	ast.Inspect(source.file, func(node ast.Node) bool {
+		if node == nil {
+			return false
+		}
+
+		if source.fset.Position(node.Pos()).Line > line || source.fset.Position(node.End()).Line < line {
+			return false
+		}
+
+		if expr, ok := node.(*ast.CallExpr); ok {
+			var fn string
+			switch fun := expr.Fun.(type) {
+			case *ast.Ident:
+				fn = fun.Name
+
+			case *ast.SelectorExpr:
+				fn = fun.Sel.Name
+
+			}
+
+			if fn == name {
+				call = expr
+			}
+		}
+
+		return true
+	})
+
+	return call
+}
+
+// findParams returns names of params of the func declared at line of the source.
+func (source *sourceFile) findParams(name string, line int) []string {
+	var params []string
+
+	for _, decl := range source.file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != name || fn.Type.Params == nil {
+			continue
+		}
+
+		if source.fset.Position(fn.Pos()).Line > line || source.fset.Position(fn.End()).Line < line {
+			continue
+		}
+
+		for _, field := range fn.Type.Params.List {
+			if len(field.Names) == 0 {
+				params = append(params, "")
+			}
+
+			for _, ident := range field.Names {
+				params = append(params, ident.Name)
+			}
+		}
+	}
+
+	return params
+}
+
+// sourceOf returns the source code of node.
+func (source *sourceFile) sourceOf(node ast.Node) string {
+	buf := new(bytes.Buffer)
+	if err := printer.Fprint(buf, source.fset, node); err != nil {
+		return ""
+	}
+
+	return buf.String()
+}
+
+// arguments are values of params by names, which are passed to Errorf by assertions for showing
+// in expressions.
+type arguments map[string]interface{}
+
+// paramLabels maps names of params to labels of values reported by assertions.
+var paramLabels = map[string][]string{
+	"actual":   {"+received"},
+	"expected": {"-expected"},
+	"element":  {"-element", "+element"},
+	"key":      {"-key"},
+	"value":    {"-value"},
+}
+
+// labelOf returns the content of the label reported for the param.
+func labelOf(labels []labeledOutput, param string) (string, bool) {
+	for _, name := range paramLabels[param] {
+		for _, label := range labels {
+			if label.label == name {
+				return label.content, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// describeExpression returns the source of the failed assertion call, with each argument expression
+// followed by its value from args or labels. It returns an empty string if the source is unavailable.
+func (c *Config) describeExpression(labels []labeledOutput, args arguments) string {
+	assertion, caller, ok := getCallSite()
+	if !ok {
+		return ""
+	}
+
+	segments := strings.Split(assertion.Function, ".")
+	name := segments[len(segments)-1]
+
+	source := parseSource(caller.File)
+	if source == nil {
+		return ""
+	}
+
+	call := source.findCall(name, caller.Line)
+	if call == nil {
+		return ""
+	}
+
+	var params []string
+	if decl := parseSource(assertion.File); decl != nil {
+		params = decl.findParams(name, assertion.Line)
+	}
+
+	// nothing to describe if all arguments are literals
+	literal := true
+	for i, arg := range call.Args {
+		if i >= len(params) || params[i] == "extras" {
+			break
+		}
+
+		if _, ok := arg.(*ast.BasicLit); !ok && params[i] != "t" {
+			literal = false
+		}
+	}
+	if literal {
+		return ""
+	}
+
+	lines := []string{source.sourceOf(call)}
+
+	for i, arg := range call.Args {
+		if i >= len(params) || params[i] == "extras" {
+			break
+		}
+
+		if _, ok := arg.(*ast.BasicLit); ok {
+			continue
+		}
+
+		value, ok := labelOf(labels, params[i])
+		if v, found := args[params[i]]; found {
+			// values of funcs are addresses only
+			if v != nil && reflect.TypeOf(v).Kind() == reflect.Func {
+				continue
+			}
+
+			value, ok = c.sprint("%#v", v), true
+		}
+		if !ok {
+			continue
+		}
+
+		expr := source.sourceOf(arg)
+		if expr == value {
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf("%s → %s", expr, value))
+	}
+
+	return strings.Join(lines, "\n")
+}
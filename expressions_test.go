@@ -0,0 +1,68 @@
+package gospec
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpression(t *testing.T) {
+	rt := new(RecordingT)
+
+	user := testingUser{Profile: &testingProfile{Age: 17}}
+
+	False(t, Equal(rt, 18, user.Profile.Age))
+
+	expected := "Hello"
+	False(t, Equal(rt,
+		expected,
+		strings.ToLower(expected),
+	))
+
+	False(t, Expect(rt, user.Name).To(MatchRegexp("^usr_")))
+
+	records := rt.Records()
+	if Len(t, records, 3) {
+		Equal(t, "Equal(rt, 18, user.Profile.Age)\nuser.Profile.Age → 17", testingLabelOf(records[0], labelExpression))
+		Equal(t, "Equal(rt,\n\texpected,\n\tstrings.ToLower(expected),\n)\nexpected → \"Hello\"\nstrings.ToLower(expected) → \"hello\"", testingLabelOf(records[1], labelExpression))
+		Equal(t, "Expect(rt, user.Name).To(MatchRegexp(\"^usr_\"))", testingLabelOf(records[2], labelExpression))
+	}
+}
+
+func TestExpressionWithoutSource(t *testing.T) {
+	Nil(t, parseSource("testdata/missing.go"))
+
+	rt := new(RecordingT)
+
+	n := 1
+	False(t, Equal(WithConfig(rt, func(c *Config) {
+		c.Expressions = false
+	}), n, 2))
+
+	records := rt.Records()
+	if Len(t, records, 1) {
+		Equal(t, "", testingLabelOf(records[0], labelExpression))
+	}
+}
+
+func TestExpressionOfValue(t *testing.T) {
+	rt := new(RecordingT)
+
+	user := testingUser{Profile: &testingProfile{Age: 17}}
+
+	False(t, Nil(rt, user.Profile))
+	False(t, Greater(rt, user.Profile.Age, 18))
+	False(t, True(rt, 1 > 2))
+	False(t, Len(rt, "Hello", 3))
+
+	records := rt.Records()
+	if Len(t, records, 4) {
+		Equal(t, FailureLabel{Label: labelExpression, Content: "Nil(rt, user.Profile)\nuser.Profile → &gospec.testingProfile{Age:17}"}, records[0].Labels[len(records[0].Labels)-1])
+		Equal(t, FailureLabel{Label: labelExpression, Content: "Greater(rt, user.Profile.Age, 18)\nuser.Profile.Age → 17"}, records[1].Labels[len(records[1].Labels)-1])
+		Equal(t, FailureLabel{Label: labelExpression, Content: "True(rt, 1 > 2)\n1 > 2 → false"}, records[2].Labels[len(records[2].Labels)-1])
+
+		// no expression for literals
+		for _, label := range records[3].Labels {
+			NotEqual(t, labelExpression, label.Label)
+		}
+	}
+}
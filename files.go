@@ -0,0 +1,535 @@
+package gospec
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// statFile returns the file info of path, reporting a failure if it does not exist.
+func statFile(t TestingT, path string, extras ...interface{}) (os.FileInfo, bool) {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, Errorf(t, fmt.Sprintf("Expect to stat file, but failed: %v", err), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-path",
+				content: path,
+			},
+		}, arguments{
+			"path": path,
+		})
+	}
+
+	return info, true
+}
+
+// FileExists asserts that the specified path exists and is not a directory.
+//
+//	assert.FileExists(t, "output/result.csv")
+//
+// Returns whether the assertion was successful (true) or not (false).
+func FileExists(t TestingT, path string, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	info, ok := statFile(t, path, extras...)
+	if !ok {
+		return false
+	}
+
+	if info.IsDir() {
+		return Errorf(t, "Expect to be a file, but got a directory", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-path",
+				content: path,
+			},
+		}, arguments{
+			"path": path,
+		})
+	}
+
+	return true
+}
+
+// DirExists asserts that the specified path exists and is a directory.
+//
+//	assert.DirExists(t, "output")
+//
+// Returns whether the assertion was successful (true) or not (false).
+func DirExists(t TestingT, path string, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	info, ok := statFile(t, path, extras...)
+	if !ok {
+		return false
+	}
+
+	if !info.IsDir() {
+		return Errorf(t, "Expect to be a directory, but got a file", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-path",
+				content: path,
+			},
+			{
+				label:   "+mode",
+				content: info.Mode().String(),
+			},
+		}, arguments{
+			"path": path,
+		})
+	}
+
+	return true
+}
+
+// NoFileExists asserts that nothing exists at the specified path, neither a file nor a directory.
+//
+//	assert.NoFileExists(t, "output/result.lock")
+//
+// Returns whether the assertion was successful (true) or not (false).
+func NoFileExists(t TestingT, path string, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	info, err := os.Lstat(path)
+	if err == nil {
+		return Errorf(t, "Expect to NOT exist", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-path",
+				content: path,
+			},
+			{
+				label:   "+mode",
+				content: info.Mode().String(),
+			},
+		}, arguments{
+			"path": path,
+		})
+	}
+
+	if !os.IsNotExist(err) {
+		return Errorf(t, fmt.Sprintf("Expect to stat file, but failed: %v", err), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-path",
+				content: path,
+			},
+		}, arguments{
+			"path": path,
+		})
+	}
+
+	return true
+}
+
+// FileMode asserts that the file at the specified path has exactly the mode, including type bits.
+//
+//	assert.FileMode(t, "bin/tool", 0755)
+//	assert.FileMode(t, "output", os.ModeDir|0700)
+//
+// Returns whether the assertion was successful (true) or not (false).
+func FileMode(t TestingT, path string, mode os.FileMode, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	info, ok := statFile(t, path, extras...)
+	if !ok {
+		return false
+	}
+
+	if info.Mode() != mode {
+		return Errorf(t, "Expect to have the file mode", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-path",
+				content: path,
+			},
+			{
+				label:   "-expected",
+				content: fmt.Sprintf("%s (%#o)", mode, uint32(mode)),
+			},
+			{
+				label:   "+received",
+				content: fmt.Sprintf("%s (%#o)", info.Mode(), uint32(info.Mode())),
+			},
+		}, arguments{
+			"path": path,
+			"mode": mode,
+		})
+	}
+
+	return true
+}
+
+// FileContains asserts that the file at the specified path contains substr, which is a string or []byte.
+// The file is streamed by chunks, so it works with huge files.
+//
+//	assert.FileContains(t, "output/app.log", "server started")
+//
+// Returns whether the assertion was successful (true) or not (false).
+func FileContains(t TestingT, path string, substr interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return Errorf(t, fmt.Sprintf("Expect to open file, but failed: %v", err), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-path",
+				content: path,
+			},
+		}, arguments{
+			"path":   path,
+			"substr": substr,
+		})
+	}
+	defer file.Close()
+
+	needle := needleOf(substr)
+
+	found, n, err := searchReader(file, needle, nil)
+	if err != nil {
+		return Errorf(t, fmt.Sprintf("Expect to read file, but failed: %v", err), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-path",
+				content: path,
+			},
+		}, arguments{
+			"path":   path,
+			"substr": substr,
+		})
+	}
+
+	if !found {
+		return Errorf(t, "Expect to include substring", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-path",
+				content: path,
+			},
+			{
+				label:   "-substring",
+				content: fmt.Sprintf("%q", needle),
+			},
+			{
+				label:   "+received",
+				content: fmt.Sprintf("%d byte(s)", n),
+			},
+		}, arguments{
+			"path":   path,
+			"substr": substr,
+		})
+	}
+
+	return true
+}
+
+// FileEqualsString asserts that the content of the file at the specified path is equal to expected.
+//
+//	assert.FileEqualsString(t, "output/VERSION", "1.2.3\n")
+//
+// Returns whether the assertion was successful (true) or not (false).
+func FileEqualsString(t TestingT, path, expected string, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Errorf(t, fmt.Sprintf("Expect to read file, but failed: %v", err), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-path",
+				content: path,
+			},
+		}, arguments{
+			"path":     path,
+			"expected": expected,
+		})
+	}
+
+	if actual := string(data); actual != expected {
+		return Errorf(t, "Expect file content to be equal", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-path",
+				content: path,
+			},
+			{
+				label:   labelDiff,
+				content: configOf(t).diff(expected, actual),
+			},
+		}, arguments{
+			"path":     path,
+			"expected": expected,
+		})
+	}
+
+	return true
+}
+
+// treeEntry is a file or directory of a tree.
+type treeEntry struct {
+	mode fs.FileMode
+}
+
+// walkTree returns all entries of fsys by slash-separated paths, excluding the root.
+// Permissions of embed.FS entries are dropped, since they're always read-only.
+func walkTree(fsys fs.FS) (map[string]treeEntry, error) {
+	entries := make(map[string]treeEntry)
+
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == "." {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		mode := info.Mode()
+		if embedded(info) {
+			mode = mode.Type()
+		}
+
+		entries[path] = treeEntry{
+			mode: mode,
+		}
+
+		return nil
+	})
+
+	return entries, err
+}
+
+// embedded returns whether info is of an entry of embed.FS, which is also true for entries of
+// an embed.FS wrapped, e.g. by fs.Sub.
+func embedded(info fs.FileInfo) bool {
+	rtype := reflect.TypeOf(info)
+	if rtype.Kind() == reflect.Ptr {
+		rtype = rtype.Elem()
+	}
+
+	return rtype.PkgPath() == "embed"
+}
+
+// sameMode returns whether two modes are the same. Permissions of directories are ignored, as well as
+// permissions of files if any of them is zero, e.g. files of testing/fstest.MapFS without modes and embed.FS.
+func sameMode(a, b fs.FileMode) bool {
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	return a.IsDir() || a.Perm() == 0 || b.Perm() == 0 || a.Perm() == b.Perm()
+}
+
+// treeChanges compares two trees, and returns missing, added and changed entries by paths.
+func (c *Config) treeChanges(ctx context.Context, expected, actual fs.FS, expentries, actentries map[string]treeEntry) (missing, added, changed []string) {
+	paths := make([]string, 0, len(expentries))
+	for path := range expentries {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		exp := expentries[path]
+
+		act, ok := actentries[path]
+		if !ok {
+			missing = append(missing, path)
+			continue
+		}
+
+		if !sameMode(exp.mode, act.mode) {
+			changed = append(changed, fmt.Sprintf("%s: mode %s != %s", path, exp.mode, act.mode))
+			continue
+		}
+
+		if !exp.mode.IsRegular() {
+			continue
+		}
+
+		if change := c.fileChange(ctx, expected, actual, path); change != "" {
+			changed = append(changed, path+": "+change)
+		}
+	}
+
+	for path := range actentries {
+		if _, ok := expentries[path]; !ok {
+			added = append(added, path)
+		}
+	}
+	sort.Strings(added)
+
+	return
+}
+
+// fileChange compares the content of the file at path in both trees, and returns the first difference.
+func (c *Config) fileChange(ctx context.Context, expected, actual fs.FS, path string) string {
+	expfile, err := expected.Open(path)
+	if err != nil {
+		return err.Error()
+	}
+	defer expfile.Close()
+
+	actfile, err := actual.Open(path)
+	if err != nil {
+		return err.Error()
+	}
+	defer actfile.Close()
+
+	mismatch, err := compareStreams(ctx, expfile, actfile, c.DiffContext)
+	if err != nil {
+		return err.Error()
+	}
+
+	if mismatch == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("differ at byte %d (line %d, column %d)\n-%s\n+%s",
+		mismatch.offset, mismatch.line, mismatch.column,
+		strings.Replace(mismatch.expected, "\n", "\n-", -1),
+		strings.Replace(mismatch.actual, "\n", "\n+", -1))
+}
+
+// DirTreeEqual asserts that the two trees have the same files and directories, by names, modes and contents.
+// It works with any fs.FS, e.g. os.DirFS, embed.FS and testing/fstest.MapFS. Permissions of directories
+// are ignored, so are permissions of files if either side has none, as files of fstest.MapFS default to,
+// or either side is an embed.FS, whose files are always read-only.
+//
+//	assert.DirTreeEqual(t, os.DirFS("testdata/golden"), os.DirFS(outputDir))
+//
+// Returns whether the assertion was successful (true) or not (false).
+func DirTreeEqual(t TestingT, expected, actual fs.FS, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	return assertTreeEqual(t, expected, actual, arguments{
+		"expected": expected,
+		"actual":   actual,
+	}, extras...)
+}
+
+// assertTreeEqual walks two trees and reports their differences. The args are params of the
+// assertion called, which are shown in expressions of failures.
+func assertTreeEqual(t TestingT, expected, actual fs.FS, args arguments, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	expentries, err := walkTree(expected)
+	if err == nil {
+		var actentries map[string]treeEntry
+
+		actentries, err = walkTree(actual)
+		if err == nil {
+			return assertTreeChanges(t, expected, actual, expentries, actentries, args, extras...)
+		}
+	}
+
+	return Errorf(t, fmt.Sprintf("Expect to walk tree, but failed: %v", err), []labeledOutput{
+		{
+			label:   labelMessages,
+			content: formatExtras(extras...),
+		},
+	}, args)
+}
+
+// assertTreeChanges reports missing, added and changed entries of two trees.
+func assertTreeChanges(t TestingT, expected, actual fs.FS, expentries, actentries map[string]treeEntry, args arguments, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	missing, added, changed := configOf(t).treeChanges(contextOf(t), expected, actual, expentries, actentries)
+	if len(missing) > 0 || len(added) > 0 || len(changed) > 0 {
+		labels := []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+		}
+		for _, label := range []labeledOutput{
+			{
+				label:   "-missing",
+				content: strings.Join(missing, "\n"),
+			},
+			{
+				label:   "+added",
+				content: strings.Join(added, "\n"),
+			},
+			{
+				label:   "Changed",
+				content: strings.Join(changed, "\n"),
+			},
+		} {
+			if label.content != "" {
+				labels = append(labels, label)
+			}
+		}
+
+		return Errorf(t, fmt.Sprintf("Expect trees to be equal, but %d missing, %d added and %d changed", len(missing), len(added), len(changed)), labels, args)
+	}
+
+	return true
+}
@@ -0,0 +1,133 @@
+package gospec
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+//go:embed testdata/tree
+var testingTree embed.FS
+
+func testingDir(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "gospec")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.MkdirAll(filepath.Join(dir, "sub"), 0755)
+	os.WriteFile(filepath.Join(dir, "hello.txt"), []byte("Hello, world!\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "sub", "tool.sh"), []byte("#!/bin/sh\necho ok\n"), 0755)
+
+	return dir
+}
+
+func TestFileAssertions(t *testing.T) {
+	mockT := new(testing.T)
+
+	dir := testingDir(t)
+	defer os.RemoveAll(dir)
+
+	hello := filepath.Join(dir, "hello.txt")
+	missing := filepath.Join(dir, "missing.txt")
+
+	True(t, FileExists(mockT, hello))
+	False(t, FileExists(mockT, dir))
+	False(t, FileExists(mockT, missing))
+
+	True(t, DirExists(mockT, dir))
+	False(t, DirExists(mockT, hello))
+	False(t, DirExists(mockT, missing))
+
+	True(t, NoFileExists(mockT, missing))
+	False(t, NoFileExists(mockT, hello))
+	False(t, NoFileExists(mockT, dir))
+
+	True(t, FileMode(mockT, filepath.Join(dir, "sub", "tool.sh"), 0755))
+	True(t, FileMode(mockT, filepath.Join(dir, "sub"), os.ModeDir|0755))
+	False(t, FileMode(mockT, hello, 0600))
+	False(t, FileMode(mockT, missing, 0644))
+
+	True(t, FileContains(mockT, hello, "world"))
+	True(t, FileContains(mockT, hello, []byte("Hello")))
+	False(t, FileContains(mockT, hello, "Salut"))
+	False(t, FileContains(mockT, missing, "Hello"))
+
+	True(t, FileEqualsString(mockT, hello, "Hello, world!\n"))
+	False(t, FileEqualsString(mockT, hello, "Hello, world!"))
+	False(t, FileEqualsString(mockT, missing, ""))
+}
+
+func TestDirTreeEqual(t *testing.T) {
+	mockT := new(testing.T)
+
+	dir := testingDir(t)
+	defer os.RemoveAll(dir)
+
+	expected := fstest.MapFS{
+		"hello.txt":   {Data: []byte("Hello, world!\n")},
+		"sub/tool.sh": {Data: []byte("#!/bin/sh\necho ok\n"), Mode: 0755},
+	}
+
+	True(t, DirTreeEqual(mockT, expected, os.DirFS(dir)))
+	True(t, DirTreeEqual(mockT, os.DirFS(dir), os.DirFS(dir)))
+	False(t, DirTreeEqual(mockT, expected, os.DirFS(filepath.Join(dir, "missing"))))
+
+	changed := fstest.MapFS{
+		"hello.txt":   {Data: []byte("Hello, gopher!\n")},
+		"sub/tool.sh": {Data: []byte("#!/bin/sh\necho ok\n"), Mode: 0600},
+		"new.txt":     {Data: []byte("new")},
+	}
+
+	os.WriteFile(filepath.Join(dir, "extra.txt"), nil, 0644)
+
+	rt := new(RecordingT)
+
+	False(t, DirTreeEqual(rt, os.DirFS(dir), changed))
+
+	records := rt.Records()
+	if Len(t, records, 1) {
+		Equal(t, "Expect trees to be equal, but 1 missing, 1 added and 2 changed", records[0].Error)
+		Equal(t, "extra.txt", testingLabelOf(records[0], "-missing"))
+		Equal(t, "new.txt", testingLabelOf(records[0], "+added"))
+		Contains(t, testingLabelOf(records[0], "Changed"), "hello.txt: differ at byte 7 (line 1, column 8)\n-     1 | Hello, world!")
+		Contains(t, testingLabelOf(records[0], "Changed"), "sub/tool.sh: mode -rwxr-xr-x != -rw-------")
+	}
+
+	// empty labels are omitted
+	rt.Reset()
+
+	False(t, DirTreeEqual(rt, expected, fstest.MapFS{
+		"hello.txt":   {Data: []byte("Hello, gopher!\n")},
+		"sub/tool.sh": {Data: []byte("#!/bin/sh\necho ok\n"), Mode: 0755},
+	}))
+	if Len(t, rt.Records(), 1) {
+		labels := rt.Records()[0].Labels
+		if Len(t, labels, 2) {
+			Equal(t, "Changed", labels[0].Label)
+		}
+	}
+}
+
+func TestDirTreeEqualWithEmbedFS(t *testing.T) {
+	mockT := new(testing.T)
+
+	dir := testingDir(t)
+	defer os.RemoveAll(dir)
+
+	// files of embed.FS are always read-only
+	expected, err := fs.Sub(testingTree, "testdata/tree")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	True(t, DirTreeEqual(mockT, expected, os.DirFS(dir)))
+	True(t, DirTreeEqual(mockT, os.DirFS(dir), expected))
+
+	os.WriteFile(filepath.Join(dir, "hello.txt"), []byte("Hello, gopher!\n"), 0644)
+
+	False(t, DirTreeEqual(mockT, expected, os.DirFS(dir)))
+}
@@ -0,0 +1,552 @@
+package gospec
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FloatPolicy defines how NaN and infinities are compared by float assertions.
+type FloatPolicy int
+
+// Float policies
+const (
+	// FloatNaNEqual considers NaN equal to NaN, which never equals anything by IEEE 754.
+	FloatNaNEqual FloatPolicy = 1 << iota
+	// FloatInfUnequal considers infinities never within any tolerance, even of the same sign.
+	FloatInfUnequal
+
+	// FloatDefault fails on NaN, and considers infinities of the same sign equal.
+	FloatDefault FloatPolicy = 0
+)
+
+// tolerance checks whether actual is within a tolerance of expected. It returns the measured
+// error and whether the error is within the tolerance. Both numerals are finite.
+type tolerance struct {
+	name  string
+	limit string
+	check func(expected, actual numeral) (string, bool)
+}
+
+// withinLimit returns value as string and whether it's within limit, which is invalid if negative or NaN.
+func withinLimit(value *big.Float, limit float64) (string, bool) {
+	ok := !math.IsNaN(limit) && limit >= 0 && value.Cmp(big.NewFloat(limit)) <= 0
+
+	f, _ := value.Float64()
+	return fmt.Sprint(f), ok
+}
+
+func deltaTolerance(delta float64) tolerance {
+	return tolerance{
+		name:  "delta",
+		limit: fmt.Sprintf("%v", delta),
+		check: func(expected, actual numeral) (string, bool) {
+			return withinLimit(expected.distance(actual), delta)
+		},
+	}
+}
+
+func epsilonTolerance(epsilon float64) tolerance {
+	return tolerance{
+		name:  "relative error",
+		limit: fmt.Sprintf("%v", epsilon),
+		check: func(expected, actual numeral) (string, bool) {
+			if expected.isZero() {
+				// relative error is undefined, only zero is acceptable
+				if actual.isZero() {
+					return "0", true
+				}
+
+				return "+Inf", false
+			}
+
+			value := expected.distance(actual)
+
+			return withinLimit(value.Quo(value, expected.abs()), epsilon)
+		},
+	}
+}
+
+func ulpsTolerance(ulps uint64) tolerance {
+	return tolerance{
+		name:  "ULPs",
+		limit: fmt.Sprintf("%d", ulps),
+		check: func(expected, actual numeral) (string, bool) {
+			if expected.complex || actual.complex {
+				return "<not real>", false
+			}
+
+			value := ulpDistance(expected.float64(), actual.float64(), expected.float32s && actual.float32s)
+
+			return fmt.Sprint(value), value <= ulps
+		},
+	}
+}
+
+// ulpDistance returns the number of representable floats between a and b, in float32 precision
+// if float32s is true.
+func ulpDistance(a, b float64, float32s bool) uint64 {
+	var oa, ob int64
+	if float32s {
+		oa = int64(orderedBits32(float32(a)))
+		ob = int64(orderedBits32(float32(b)))
+	} else {
+		oa = orderedBits64(a)
+		ob = orderedBits64(b)
+	}
+
+	if oa > ob {
+		return uint64(oa) - uint64(ob)
+	}
+
+	return uint64(ob) - uint64(oa)
+}
+
+// orderedBits64 maps bits of f to an integer ordered as floats, with -0 and +0 both mapped to 0.
+func orderedBits64(f float64) int64 {
+	bits := math.Float64bits(f)
+	if bits>>63 == 1 {
+		return -int64(bits &^ (1 << 63))
+	}
+
+	return int64(bits)
+}
+
+func orderedBits32(f float32) int32 {
+	bits := math.Float32bits(f)
+	if bits>>31 == 1 {
+		return -int32(bits &^ (1 << 31))
+	}
+
+	return int32(bits)
+}
+
+// within checks actual against expected by tol with the NaN and infinity policy. The returned
+// string explains the failure.
+func (tol tolerance) within(expected, actual numeral, policy FloatPolicy) (string, bool) {
+	if expected.nan || actual.nan {
+		if expected.nan && actual.nan && policy&FloatNaNEqual != 0 {
+			return "", true
+		}
+
+		return "NaN is not comparable", false
+	}
+
+	if expected.isInf() || actual.isInf() {
+		if expected.equal(actual) && policy&FloatInfUnequal == 0 {
+			return "", true
+		}
+
+		return "infinity is not within any tolerance", false
+	}
+
+	value, ok := tol.check(expected, actual)
+	if !ok {
+		return fmt.Sprintf("%s %s > %s", tol.name, value, tol.limit), false
+	}
+
+	return "", true
+}
+
+// assertTolerance asserts two numerals are within tol of each other.
+func assertTolerance(t TestingT, expected, actual interface{}, tol tolerance, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	exp, expok := numeralOf(expected)
+	act, actok := numeralOf(actual)
+	if !expok || !actok {
+		exps, acts := configOf(t).toString(expected, actual)
+
+		return Errorf(t, "Parameters must be numerical", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-expected",
+				content: exps,
+			},
+			{
+				label:   "+received",
+				content: acts,
+			},
+		}, arguments{
+			"expected": expected,
+			"actual":   actual,
+		})
+	}
+
+	if reason, ok := tol.within(exp, act, configOf(t).FloatPolicy); !ok {
+		exps, acts := configOf(t).toString(expected, actual)
+
+		return Errorf(t, fmt.Sprintf("Expect the %s between two numbers within %s", tol.name, tol.limit), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-expected",
+				content: exps,
+			},
+			{
+				label:   "+received",
+				content: acts,
+			},
+			{
+				label:   "+calculated:",
+				content: reason,
+			},
+		}, arguments{
+			"expected": expected,
+			"actual":   actual,
+		})
+	}
+
+	return true
+}
+
+// InEpsilon asserts that the relative error between two numerals, |expected - actual| / |expected|,
+// is within epsilon. An expected zero only accepts an actual zero.
+//
+//	assert.InEpsilon(t, 1e9, 1.0001e9, 0.001)
+//
+// Returns whether the assertion was successful (true) or not (false).
+func InEpsilon(t TestingT, expected, actual interface{}, epsilon float64, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	return assertTolerance(t, expected, actual, epsilonTolerance(epsilon), extras...)
+}
+
+// InULPs asserts that two floats are within ulps units in the last place of each other,
+// which are counted in float32 precision if both of them are float32.
+//
+//	assert.InULPs(t, 0.3, a+b, 1)
+//
+// Returns whether the assertion was successful (true) or not (false).
+func InULPs(t TestingT, expected, actual interface{}, ulps uint64, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	return assertTolerance(t, expected, actual, ulpsTolerance(ulps), extras...)
+}
+
+// toleranceViolation is an element of composite values exceeding tolerance.
+type toleranceViolation struct {
+	path             string
+	expected, actual string
+	reason           string
+}
+
+// collectViolations walks expected and actual of the same shape, and collects numeric elements
+// exceeding tol, and elements mismatched in shape. Pointer pairs in visited are those of the
+// current path, which are not followed again.
+func collectViolations(c *Config, path string, expected, actual reflect.Value, tol tolerance, visited map[[2]uintptr]bool, violations *[]toleranceViolation) {
+	violate := func(reason string) {
+		*violations = append(*violations, toleranceViolation{
+			path:     path,
+			expected: c.valueString(expected),
+			actual:   c.valueString(actual),
+			reason:   reason,
+		})
+	}
+
+	if !expected.IsValid() || !actual.IsValid() {
+		violate("missing element")
+		return
+	}
+
+	expected, expptr := derefValue(expected)
+	actual, actptr := derefValue(actual)
+	if expptr != 0 || actptr != 0 {
+		pair := [2]uintptr{expptr, actptr}
+		if visited[pair] {
+			return
+		}
+
+		if visited == nil {
+			visited = make(map[[2]uintptr]bool)
+		}
+
+		visited[pair] = true
+		defer delete(visited, pair)
+	}
+
+	if exp, ok := numeralOfValue(expected); ok {
+		act, ok := numeralOfValue(actual)
+		if !ok {
+			violate("not numerical")
+			return
+		}
+
+		if reason, ok := tol.within(exp, act, c.FloatPolicy); !ok {
+			violate(reason)
+		}
+
+		return
+	}
+
+	if listKind(expected.Kind()) != listKind(actual.Kind()) {
+		violate("mismatched kind")
+		return
+	}
+
+	switch expected.Kind() {
+	case reflect.Slice, reflect.Array:
+		n := expected.Len()
+		if actual.Len() > n {
+			n = actual.Len()
+		}
+
+		for i := 0; i < n; i++ {
+			var exp, act reflect.Value
+			if i < expected.Len() {
+				exp = expected.Index(i)
+			}
+			if i < actual.Len() {
+				act = actual.Index(i)
+			}
+
+			collectViolations(c, fmt.Sprintf("%s[%d]", path, i), exp, act, tol, visited, violations)
+		}
+
+	case reflect.Map:
+		if expected.Type().Key() != actual.Type().Key() {
+			violate("mismatched key type")
+			return
+		}
+
+		keys := append([]reflect.Value{}, expected.MapKeys()...)
+		for _, key := range actual.MapKeys() {
+			if !expected.MapIndex(key).IsValid() {
+				keys = append(keys, key)
+			}
+		}
+
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprintf("%v", keys[i]) < fmt.Sprintf("%v", keys[j])
+		})
+
+		for _, key := range keys {
+			collectViolations(c, fmt.Sprintf("%s[%#v]", path, key), expected.MapIndex(key), actual.MapIndex(key), tol, visited, violations)
+		}
+
+	case reflect.Struct:
+		if expected.Type() != actual.Type() {
+			violate("mismatched type")
+			return
+		}
+
+		for i := 0; i < expected.NumField(); i++ {
+			collectViolations(c, path+"."+expected.Type().Field(i).Name, expected.Field(i), actual.Field(i), tol, visited, violations)
+		}
+
+	default:
+		// non-numeric elements must be equal
+		if c.valueString(expected) != c.valueString(actual) {
+			violate("not equal")
+		}
+
+	}
+}
+
+// derefValue follows pointers, except those of big numbers, and interfaces of rval until a nil
+// or a pointer followed already, and returns the value reached with the first pointer followed,
+// which is 0 if there is none.
+func derefValue(rval reflect.Value) (reflect.Value, uintptr) {
+	var (
+		first   uintptr
+		visited map[uintptr]bool
+	)
+
+	for (rval.Kind() == reflect.Ptr && !isBigType(rval.Type())) || rval.Kind() == reflect.Interface {
+		if rval.IsNil() {
+			break
+		}
+
+		if rval.Kind() == reflect.Ptr {
+			if visited[rval.Pointer()] {
+				break
+			}
+
+			if first == 0 {
+				first = rval.Pointer()
+			}
+
+			visited = visit(visited, rval)
+		}
+
+		rval = rval.Elem()
+	}
+
+	return rval, first
+}
+
+// valueString returns a string representation of rval, which may be obtained from unexported fields.
+func (c *Config) valueString(rval reflect.Value) string {
+	if !rval.IsValid() {
+		return "<missing>"
+	}
+
+	if rval.CanInterface() {
+		return c.sprint("%#v", rval.Interface())
+	}
+
+	return fmt.Sprintf("%#v", rval)
+}
+
+// listKind returns reflect.Slice for arrays, which are compared with slices element by element.
+func listKind(kind reflect.Kind) reflect.Kind {
+	if kind == reflect.Array {
+		return reflect.Slice
+	}
+
+	return kind
+}
+
+// assertToleranceAll asserts all numeric elements of expected and actual of kind are within tol.
+func assertToleranceAll(t TestingT, expected, actual interface{}, kind reflect.Kind, tol tolerance, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	expval, actval := reflect.ValueOf(expected), reflect.ValueOf(actual)
+
+	kindOf := func(rval reflect.Value) reflect.Kind {
+		if rval.Kind() == reflect.Ptr && !rval.IsNil() && !isBigType(rval.Type()) {
+			rval = rval.Elem()
+		}
+
+		return listKind(rval.Kind())
+	}
+
+	if kindOf(expval) != kind || kindOf(actval) != kind {
+		exps, acts := configOf(t).toString(expected, actual)
+
+		return Errorf(t, fmt.Sprintf("Parameters must be of %s", kind), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-expected",
+				content: exps,
+			},
+			{
+				label:   "+received",
+				content: acts,
+			},
+		}, arguments{
+			"expected": expected,
+			"actual":   actual,
+		})
+	}
+
+	var violations []toleranceViolation
+	collectViolations(configOf(t), "", expval, actval, tol, nil, &violations)
+	if len(violations) == 0 {
+		return true
+	}
+
+	lines := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		lines = append(lines, fmt.Sprintf("%s: expected %s, received %s, %s", violation.path, violation.expected, violation.actual, violation.reason))
+	}
+
+	return Errorf(t, fmt.Sprintf("Expect the %s of all elements within %s, but %d element(s) exceed", tol.name, tol.limit, len(violations)), []labeledOutput{
+		{
+			label:   labelMessages,
+			content: formatExtras(extras...),
+		},
+		{
+			label:   "Violations",
+			content: strings.Join(lines, "\n"),
+		},
+	}, arguments{
+		"expected": expected,
+		"actual":   actual,
+	})
+}
+
+// InDeltaSlice asserts that elements of two slices or arrays are within delta of each other pairwise,
+// and reports every element exceeding.
+//
+//	assert.InDeltaSlice(t, []float64{1, 2}, []float64{1.01, 1.99}, 0.1)
+//
+// Returns whether the assertion was successful (true) or not (false).
+func InDeltaSlice(t TestingT, expected, actual interface{}, delta float64, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	return assertToleranceAll(t, expected, actual, reflect.Slice, deltaTolerance(delta), extras...)
+}
+
+// InDeltaMapValues asserts that values of two maps are within delta of each other by key,
+// and reports every value exceeding and every key missing.
+//
+// Returns whether the assertion was successful (true) or not (false).
+func InDeltaMapValues(t TestingT, expected, actual interface{}, delta float64, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	return assertToleranceAll(t, expected, actual, reflect.Map, deltaTolerance(delta), extras...)
+}
+
+// InDeltaFields asserts that numeric fields of two structs are within delta of each other,
+// and reports every field exceeding.
+//
+// Returns whether the assertion was successful (true) or not (false).
+func InDeltaFields(t TestingT, expected, actual interface{}, delta float64, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	return assertToleranceAll(t, expected, actual, reflect.Struct, deltaTolerance(delta), extras...)
+}
+
+// InEpsilonSlice asserts that the relative errors between elements of two slices or arrays are
+// within epsilon pairwise, and reports every element exceeding.
+//
+// Returns whether the assertion was successful (true) or not (false).
+func InEpsilonSlice(t TestingT, expected, actual interface{}, epsilon float64, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	return assertToleranceAll(t, expected, actual, reflect.Slice, epsilonTolerance(epsilon), extras...)
+}
+
+// InEpsilonMapValues asserts that the relative errors between values of two maps are within
+// epsilon by key, and reports every value exceeding and every key missing.
+//
+//	assert.InEpsilonMapValues(t, map[string]float64{"a": 1e6}, map[string]float64{"a": 1.00001e6}, 1e-4)
+//
+// Returns whether the assertion was successful (true) or not (false).
+func InEpsilonMapValues(t TestingT, expected, actual interface{}, epsilon float64, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	return assertToleranceAll(t, expected, actual, reflect.Map, epsilonTolerance(epsilon), extras...)
+}
+
+// InEpsilonFields asserts that the relative errors between numeric fields of two structs are
+// within epsilon, and reports every field exceeding.
+//
+// Returns whether the assertion was successful (true) or not (false).
+func InEpsilonFields(t TestingT, expected, actual interface{}, epsilon float64, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	return assertToleranceAll(t, expected, actual, reflect.Struct, epsilonTolerance(epsilon), extras...)
+}
@@ -0,0 +1,158 @@
+package gospec
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInEpsilon(t *testing.T) {
+	mockT := new(testing.T)
+
+	trueCases := []struct {
+		a, b    interface{}
+		epsilon float64
+	}{
+		{1e9, 1.0001e9, 0.001},
+		{1e-9, 1.0001e-9, 0.001},
+		{uint8(100), uint8(101), 0.01},
+		{int64(-100), int64(-101), 0.01},
+		{float32(1), float32(1.001), 0.01},
+		{0, 0, 0},
+		{math.Inf(1), math.Inf(1), 0},
+	}
+
+	for i, tc := range trueCases {
+		True(t, InEpsilon(mockT, tc.a, tc.b, tc.epsilon), "InEpsilon should return true for trueCases(%d)", i)
+	}
+
+	falseCases := []struct {
+		a, b    interface{}
+		epsilon float64
+	}{
+		{1e9, 1.1e9, 0.001},
+		{1e-9, 2e-9, 0.5},
+		{0, 1e-12, 0.1},
+		{math.NaN(), math.NaN(), 1},
+		{math.Inf(1), math.Inf(-1), 1},
+		{math.Inf(1), 1e308, 1},
+		{"", 0, 1},
+		{nil, 0, 1},
+	}
+
+	for i, fc := range falseCases {
+		False(t, InEpsilon(mockT, fc.a, fc.b, fc.epsilon), "InEpsilon should return false for falseCases(%d)", i)
+	}
+}
+
+func TestInULPs(t *testing.T) {
+	mockT := new(testing.T)
+
+	a, b := 0.1, 0.2
+
+	True(t, InULPs(mockT, 0.3, a+b, 1))
+	False(t, InULPs(mockT, 0.3, a+b, 0))
+	True(t, InULPs(mockT, 0.0, math.Copysign(0, -1), 0))
+	True(t, InULPs(mockT, math.SmallestNonzeroFloat64, -math.SmallestNonzeroFloat64, 2))
+	True(t, InULPs(mockT, float32(1), math.Nextafter32(1, 2), 1))
+	False(t, InULPs(mockT, float32(1), float32(1.001), 1))
+
+	Equal(t, uint64(1), ulpDistance(1, math.Nextafter(1, 2), false))
+	Equal(t, uint64(1), ulpDistance(float64(float32(1)), float64(math.Nextafter32(1, 0)), true))
+}
+
+func TestConfig_FloatPolicy(t *testing.T) {
+	mockT := new(testing.T)
+
+	assert := WithConfig(mockT, func(c *Config) {
+		c.FloatPolicy = FloatNaNEqual | FloatInfUnequal
+	})
+	True(t, InEpsilon(assert, math.NaN(), math.NaN(), 0))
+	True(t, InDelta(assert, math.NaN(), math.NaN(), 0))
+	False(t, InEpsilon(assert, math.NaN(), 1, 0))
+	False(t, InEpsilon(assert, math.Inf(1), math.Inf(1), 1))
+	False(t, InDelta(assert, math.Inf(1), math.Inf(1), 1))
+
+	True(t, InDelta(mockT, math.Inf(-1), math.Inf(-1), 0))
+	False(t, InDelta(mockT, math.NaN(), math.NaN(), 0))
+}
+
+func TestInDeltaSlice(t *testing.T) {
+	mockT := new(testing.T)
+
+	True(t, InDeltaSlice(mockT, []float64{1, 2, 3}, []float64{1.01, 1.99, 3}, 0.1))
+	True(t, InDeltaSlice(mockT, [2]int{1, 2}, []int{2, 3}, 1))
+	False(t, InDeltaSlice(mockT, []float64{1, 2, 3}, []float64{1, 2}, 0.1))
+	False(t, InDeltaSlice(mockT, []float64{1}, map[int]float64{0: 1}, 0.1))
+	True(t, InDeltaSlice(mockT, []string{"a"}, []string{"a"}, 0.1))
+	False(t, InDeltaSlice(mockT, []string{"a"}, []string{"b"}, 0.1))
+
+	rt := new(RecordingT)
+
+	False(t, InDeltaSlice(rt, []float64{1, 2, 3, 4}, []float64{1.5, 2, 3.5, 4}, 0.1))
+
+	records := rt.Records()
+	if Len(t, records, 1) {
+		Contains(t, records[0].Error, "but 2 element(s) exceed")
+		Contains(t, rt.String(), "[0]: expected 1, received 1.5, delta 0.5 > 0.1\n")
+		Contains(t, rt.String(), "[2]: expected 3, received 3.5, delta 0.5 > 0.1")
+	}
+}
+
+func TestInEpsilonMapValues(t *testing.T) {
+	mockT := new(testing.T)
+
+	True(t, InEpsilonMapValues(mockT, map[string]float64{"a": 1e6, "b": 1e-6}, map[string]float64{"a": 1.00001e6, "b": 1.00001e-6}, 1e-4))
+	False(t, InEpsilonMapValues(mockT, map[string]float64{"a": 1e6}, map[string]float64{"a": 1.1e6}, 1e-4))
+	False(t, InEpsilonMapValues(mockT, map[string]float64{"a": 1e6}, map[string]float64{"b": 1e6}, 1e-4))
+	True(t, InDeltaMapValues(mockT, map[string][]int{"a": {1, 2}}, map[string][]int{"a": {2, 1}}, 1))
+	False(t, InDeltaMapValues(mockT, map[string][]int{"a": {1, 2}}, map[string][]int{"a": {3, 1}}, 1))
+
+	// keys of different types
+	rt := new(RecordingT)
+
+	False(t, InDeltaMapValues(rt, map[string]float64{"a": 1}, map[int]float64{1: 1}, 0.1))
+	Contains(t, rt.String(), "mismatched key type")
+}
+
+func TestInDeltaFields(t *testing.T) {
+	mockT := new(testing.T)
+
+	type point struct {
+		X, Y float64
+		name string
+	}
+
+	True(t, InDeltaFields(mockT, point{1, 2, ""}, point{1.01, 2.01, ""}, 0.1))
+	True(t, InEpsilonFields(mockT, &point{1, 2, ""}, &point{1.01, 2.01, ""}, 0.1))
+	False(t, InDeltaFields(mockT, point{1, 2, ""}, point{1, 3, ""}, 0.1))
+	False(t, InDeltaFields(mockT, point{1, 2, ""}, point{1, 2, "name"}, 0.1))
+}
+
+func TestInDeltaFieldsWithCycles(t *testing.T) {
+	mockT := new(testing.T)
+
+	type node struct {
+		Value float64
+		Next  *node
+	}
+
+	expected, actual := &node{Value: 1}, &node{Value: 1.01}
+	expected.Next, actual.Next = expected, actual
+
+	True(t, InDeltaFields(mockT, expected, actual, 0.1))
+	False(t, InEpsilonFields(mockT, expected, actual, 0.001))
+
+	// cycles of different lengths
+	other := &node{Value: 1, Next: &node{Value: 1}}
+	other.Next.Next = other
+
+	True(t, InDeltaFields(mockT, expected, other, 0.1))
+
+	// pointers to themselves
+	type pointer *pointer
+
+	var p pointer
+	p = &p
+
+	True(t, InDeltaSlice(mockT, []interface{}{p, 1.0}, []interface{}{p, 1.0}, 0.1))
+}
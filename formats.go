@@ -0,0 +1,653 @@
+package gospec
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Formatter formats values of a registered type for failure output.
+type Formatter func(v interface{}) string
+
+var (
+	formattersMux sync.RWMutex
+	formatters    = map[reflect.Type]Formatter{}
+)
+
+// RegisterFormatter registers fn for formatting values of the type of typ in failure output,
+// which is used by toString, diff and assertions built on them. typ can be a value or a reflect.Type.
+//
+//	gospec.RegisterFormatter(decimal.Decimal{}, func(v interface{}) string {
+//		return v.(decimal.Decimal).String()
+//	})
+//
+// A nil fn removes the formatter registered for the type.
+func RegisterFormatter(typ interface{}, fn Formatter) {
+	rtype, ok := typ.(reflect.Type)
+	if !ok {
+		rtype = reflect.TypeOf(typ)
+	}
+	if rtype == nil {
+		return
+	}
+
+	formattersMux.Lock()
+	defer formattersMux.Unlock()
+
+	if fn == nil {
+		delete(formatters, rtype)
+		return
+	}
+
+	formatters[rtype] = fn
+}
+
+// lookupFormatter returns the formatter registered for values of rtype.
+func lookupFormatter(rtype reflect.Type) (Formatter, bool) {
+	formattersMux.RLock()
+	defer formattersMux.RUnlock()
+
+	fn, ok := formatters[rtype]
+	return fn, ok
+}
+
+// format returns v formatted by its registered formatter, or by its GoString or String method
+// if Stringers of the config is enabled.
+func (c *Config) format(v interface{}) (string, bool) {
+	if v == nil {
+		return "", false
+	}
+
+	if fn, ok := lookupFormatter(reflect.TypeOf(v)); ok {
+		return fn(v), true
+	}
+
+	if !c.Stringers {
+		return "", false
+	}
+
+	// methods may not accept nil receivers, which are formatted by fmt
+	if rval := reflect.ValueOf(v); rval.Kind() == reflect.Ptr && rval.IsNil() {
+		return "", false
+	}
+
+	switch sv := v.(type) {
+	case fmt.GoStringer:
+		return callStringer(sv.GoString)
+
+	case fmt.Stringer:
+		return callStringer(sv.String)
+
+	}
+
+	return "", false
+}
+
+// callStringer returns the result of fn, or false if fn panics, like fmt does for methods of values.
+func callStringer(fn func() string) (s string, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			s, ok = "", false
+		}
+	}()
+
+	return fn(), true
+}
+
+// sprint returns v formatted by format, or by the fmt verb otherwise. Go syntax values containing
+// values of registered types, or exceeding limits, are formatted by dumpValue in compact mode,
+// so nested values are formatted the same way as diffs.
+func (c *Config) sprint(verb string, v interface{}) string {
+	return c.sprintOf(verb, v, nil)
+}
+
+// sprintOf returns v formatted as sprint does, with elements differing from other kept by limits.
+func (c *Config) sprintOf(verb string, v, other interface{}) string {
+	if s, ok := c.format(v); ok {
+		return s
+	}
+
+	if verb == "%#v" && v != nil && (c.formattable(reflect.TypeOf(v)) || c.exceedsLimits(reflect.ValueOf(v), 0, nil)) {
+		buf := new(strings.Builder)
+
+		c.dumpValue(buf, reflect.ValueOf(v), reflect.ValueOf(other), 0, nil, true)
+
+		return buf.String()
+	}
+
+	return fmt.Sprintf(verb, v)
+}
+
+// formattable returns true if values of rtype or any of their elements, fields, keys
+// are formatted by format.
+func (c *Config) formattable(rtype reflect.Type) bool {
+	formattersMux.RLock()
+	empty := len(formatters) == 0
+	formattersMux.RUnlock()
+
+	if empty && !c.Stringers {
+		return false
+	}
+
+	return c.reachFormatter(rtype, map[reflect.Type]bool{})
+}
+
+var (
+	goStringerType = reflect.TypeOf((*fmt.GoStringer)(nil)).Elem()
+	stringerType   = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+func (c *Config) reachFormatter(rtype reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[rtype] {
+		return false
+	}
+	visited[rtype] = true
+
+	if _, ok := lookupFormatter(rtype); ok {
+		return true
+	}
+
+	if c.Stringers && (rtype.Implements(goStringerType) || rtype.Implements(stringerType)) {
+		return true
+	}
+
+	switch rtype.Kind() {
+	case reflect.Interface:
+		// dynamic values may be of any type
+		return true
+
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return c.reachFormatter(rtype.Elem(), visited)
+
+	case reflect.Map:
+		return c.reachFormatter(rtype.Key(), visited) || c.reachFormatter(rtype.Elem(), visited)
+
+	case reflect.Struct:
+		for i := 0; i < rtype.NumField(); i++ {
+			if c.reachFormatter(rtype.Field(i).Type, visited) {
+				return true
+			}
+		}
+
+	}
+
+	return false
+}
+
+// dump returns a multi-line representation of v, similar to spew dumps, with values of
+// registered types formatted by their formatters, and elements elided by limits except those
+// differing from other. It's used for diffing values which spew can't format so.
+func (c *Config) dump(v, other interface{}) string {
+	buf := new(strings.Builder)
+
+	c.dumpValue(buf, reflect.ValueOf(v), reflect.ValueOf(other), 0, nil, false)
+	buf.WriteString("\n")
+
+	return buf.String()
+}
+
+// exceedsLimits returns whether rval has more elements or levels than MaxElements or MaxDepth
+// of limits, so it must be formatted by dumpValue to elide them. Pointers in visited are those
+// of the current path, which are not followed again.
+func (c *Config) exceedsLimits(rval reflect.Value, depth int, visited map[uintptr]bool) bool {
+	l := c.Limits
+	if (l.MaxElements <= 0 && l.MaxDepth <= 0) || !rval.IsValid() || depth > 32 {
+		return false
+	}
+
+	switch rval.Kind() {
+	case reflect.Interface:
+		return !rval.IsNil() && c.exceedsLimits(rval.Elem(), depth, visited)
+
+	case reflect.Ptr:
+		if rval.IsNil() || visited[rval.Pointer()] {
+			return false
+		}
+
+		visited = visit(visited, rval)
+		defer delete(visited, rval.Pointer())
+
+		return c.exceedsLimits(rval.Elem(), depth, visited)
+
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		if l.MaxDepth > 0 && depth >= l.MaxDepth {
+			return true
+		}
+
+	default:
+		return false
+
+	}
+
+	switch rval.Kind() {
+	case reflect.Struct:
+		if l.MaxElements > 0 && rval.NumField() > l.MaxElements {
+			return true
+		}
+
+		for i := 0; i < rval.NumField(); i++ {
+			if c.exceedsLimits(rval.Field(i), depth+1, visited) {
+				return true
+			}
+		}
+
+	case reflect.Map:
+		if l.MaxElements > 0 && rval.Len() > l.MaxElements {
+			return true
+		}
+
+		for iter := rval.MapRange(); iter.Next(); {
+			if c.exceedsLimits(iter.Value(), depth+1, visited) {
+				return true
+			}
+		}
+
+	default:
+		if l.MaxElements > 0 && rval.Len() > l.MaxElements {
+			return true
+		}
+
+		for i := 0; i < rval.Len(); i++ {
+			if c.exceedsLimits(rval.Index(i), depth+1, visited) {
+				return true
+			}
+		}
+
+	}
+
+	return false
+}
+
+// sameValue returns whether a and b are deeply equal, which may be obtained from unexported fields.
+func sameValue(a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	if a.CanInterface() && b.CanInterface() {
+		return reflect.DeepEqual(a.Interface(), b.Interface())
+	}
+
+	return fmt.Sprintf("%#v", a) == fmt.Sprintf("%#v", b)
+}
+
+// counterpart returns the value of other corresponding to rval, which is of the same type.
+func counterpart(rval, other reflect.Value) reflect.Value {
+	if other.IsValid() && other.Type() == rval.Type() {
+		return other
+	}
+
+	return reflect.Value{}
+}
+
+// elidedLevel returns whether the composite rval is nested beyond MaxDepth of limits,
+// and it does not differ from other.
+func (c *Config) elidedLevel(rval, other reflect.Value, depth int) bool {
+	if c.Limits.MaxDepth <= 0 || depth < c.Limits.MaxDepth {
+		return false
+	}
+
+	switch rval.Kind() {
+	case reflect.Slice, reflect.Map:
+		if rval.IsNil() {
+			return false
+		}
+
+	case reflect.Array, reflect.Struct:
+		// composite
+
+	default:
+		return false
+
+	}
+
+	return !other.IsValid() || sameValue(rval, other)
+}
+
+// keptElements returns which of n elements are kept within MaxElements of limits. Elements beyond
+// are kept if they differ from their counterparts, up to another MaxElements of them.
+func (c *Config) keptElements(n int, differs func(i int) bool) []bool {
+	kept := make([]bool, n)
+
+	max := c.Limits.MaxElements
+	for i, extra := 0, 0; i < n; i++ {
+		switch {
+		case max <= 0 || i < max:
+			kept[i] = true
+
+		case extra < max && differs(i):
+			kept[i] = true
+			extra++
+
+		}
+	}
+
+	return kept
+}
+
+// elisionOf returns the marker of n elided elements.
+func elisionOf(n int) string {
+	return fmt.Sprintf("... %d more elements", n)
+}
+
+// dumpElements writes n elements to buf by dumpElement, with elements not kept by limits elided
+// as indented markers, separated by sep and each followed by end.
+func (c *Config) dumpElements(buf *strings.Builder, n int, differs func(i int) bool, sep, indent, end string, dumpElement func(i int)) {
+	kept := c.keptElements(n, differs)
+
+	written := 0
+	separate := func() {
+		if written > 0 {
+			buf.WriteString(sep)
+		}
+		written++
+	}
+
+	for i := 0; i < n; {
+		if kept[i] {
+			separate()
+			dumpElement(i)
+			buf.WriteString(end)
+
+			i++
+			continue
+		}
+
+		elided := 0
+		for ; i < n && !kept[i]; i++ {
+			elided++
+		}
+
+		separate()
+		buf.WriteString(indent + elisionOf(elided))
+		buf.WriteString(end)
+	}
+}
+
+// dumpValue writes rval to buf with values of registered types formatted by their formatters,
+// either as a multi-line dump or in compact mode as Go syntax like %#v. Elements and levels
+// exceeding limits are elided, except those differing from other, the counterpart of rval.
+// Pointers in visited are those of the current path, which are marked as already shown.
+func (c *Config) dumpValue(buf *strings.Builder, rval, other reflect.Value, depth int, visited map[uintptr]bool, compact bool) {
+	if !rval.IsValid() {
+		buf.WriteString("<nil>")
+		return
+	}
+
+	if rval.CanInterface() {
+		if s, ok := c.format(rval.Interface()); ok {
+			buf.WriteString(s)
+			return
+		}
+	}
+
+	// cyclic values are cut off by depth
+	if depth > 32 {
+		buf.WriteString("...")
+		return
+	}
+
+	other = counterpart(rval, other)
+
+	if c.elidedLevel(rval, other, depth) {
+		if compact {
+			fmt.Fprintf(buf, "%s{...}", rval.Type())
+		} else {
+			fmt.Fprintf(buf, "(%s) {...}", rval.Type())
+		}
+
+		return
+	}
+
+	if compact {
+		c.dumpCompact(buf, rval, other, depth, visited)
+		return
+	}
+
+	indent := strings.Repeat(" ", depth+1)
+
+	switch rval.Kind() {
+	case reflect.Ptr:
+		if rval.IsNil() {
+			fmt.Fprintf(buf, "(%s)(nil)", rval.Type())
+			return
+		}
+
+		buf.WriteString("&")
+		if visited[rval.Pointer()] {
+			buf.WriteString("<already shown>")
+			return
+		}
+
+		visited = visit(visited, rval)
+		defer delete(visited, rval.Pointer())
+
+		c.dumpValue(buf, rval.Elem(), elemOf(other), depth, visited, false)
+
+	case reflect.Interface:
+		if rval.IsNil() {
+			buf.WriteString("<nil>")
+			return
+		}
+
+		c.dumpValue(buf, rval.Elem(), elemOf(other), depth, visited, false)
+
+	case reflect.Struct:
+		fmt.Fprintf(buf, "(%s) {\n", rval.Type())
+
+		c.dumpElements(buf, rval.NumField(), func(i int) bool {
+			return other.IsValid() && !sameValue(rval.Field(i), other.Field(i))
+		}, "", indent, ",\n", func(i int) {
+			buf.WriteString(indent + rval.Type().Field(i).Name + ": ")
+			c.dumpValue(buf, rval.Field(i), fieldOf(other, i), depth+1, visited, false)
+		})
+
+		buf.WriteString(indent[1:] + "}")
+
+	case reflect.Slice, reflect.Array:
+		if rval.Kind() == reflect.Slice && rval.IsNil() {
+			fmt.Fprintf(buf, "(%s) <nil>", rval.Type())
+			return
+		}
+
+		fmt.Fprintf(buf, "(%s) (len=%d) {\n", rval.Type(), rval.Len())
+
+		c.dumpElements(buf, rval.Len(), func(i int) bool {
+			return other.IsValid() && !sameValue(rval.Index(i), indexOf(other, i))
+		}, "", indent, ",\n", func(i int) {
+			buf.WriteString(indent)
+			c.dumpValue(buf, rval.Index(i), indexOf(other, i), depth+1, visited, false)
+		})
+
+		buf.WriteString(indent[1:] + "}")
+
+	case reflect.Map:
+		if rval.IsNil() {
+			fmt.Fprintf(buf, "(%s) <nil>", rval.Type())
+			return
+		}
+
+		fmt.Fprintf(buf, "(%s) (len=%d) {\n", rval.Type(), rval.Len())
+
+		keys := rval.MapKeys()
+		names := make([]string, len(keys))
+		for i, key := range keys {
+			keyBuf := new(strings.Builder)
+			c.dumpValue(keyBuf, key, reflect.Value{}, depth+1, visited, false)
+
+			names[i] = keyBuf.String()
+		}
+
+		if c.SortMapKeys {
+			sort.Sort(keyNames{keys, names})
+		}
+
+		c.dumpElements(buf, len(keys), func(i int) bool {
+			return other.IsValid() && !sameValue(rval.MapIndex(keys[i]), other.MapIndex(keys[i]))
+		}, "", indent, ",\n", func(i int) {
+			buf.WriteString(indent + names[i] + ": ")
+			c.dumpValue(buf, rval.MapIndex(keys[i]), mapIndexOf(other, keys[i]), depth+1, visited, false)
+		})
+
+		buf.WriteString(indent[1:] + "}")
+
+	default:
+		fmt.Fprintf(buf, "(%s) %#v", rval.Type(), rval)
+
+	}
+}
+
+// dumpCompact writes rval to buf as Go syntax like %#v, with its elements, fields and keys
+// written by dumpValue in compact mode.
+func (c *Config) dumpCompact(buf *strings.Builder, rval, other reflect.Value, depth int, visited map[uintptr]bool) {
+	switch rval.Kind() {
+	case reflect.Ptr:
+		if rval.IsNil() {
+			fmt.Fprintf(buf, "(%s)(nil)", rval.Type())
+			return
+		}
+
+		buf.WriteString("&")
+		if visited[rval.Pointer()] {
+			buf.WriteString("<already shown>")
+			return
+		}
+
+		visited = visit(visited, rval)
+		defer delete(visited, rval.Pointer())
+
+		c.dumpValue(buf, rval.Elem(), elemOf(other), depth, visited, true)
+
+	case reflect.Interface:
+		if rval.IsNil() {
+			fmt.Fprintf(buf, "%s(nil)", rval.Type())
+			return
+		}
+
+		c.dumpValue(buf, rval.Elem(), elemOf(other), depth, visited, true)
+
+	case reflect.Struct:
+		fmt.Fprintf(buf, "%s{", rval.Type())
+
+		c.dumpElements(buf, rval.NumField(), func(i int) bool {
+			return other.IsValid() && !sameValue(rval.Field(i), other.Field(i))
+		}, ", ", "", "", func(i int) {
+			buf.WriteString(rval.Type().Field(i).Name + ":")
+			c.dumpValue(buf, rval.Field(i), fieldOf(other, i), depth+1, visited, true)
+		})
+
+		buf.WriteString("}")
+
+	case reflect.Slice, reflect.Array:
+		if rval.Kind() == reflect.Slice && rval.IsNil() {
+			fmt.Fprintf(buf, "%s(nil)", rval.Type())
+			return
+		}
+
+		fmt.Fprintf(buf, "%s{", rval.Type())
+
+		c.dumpElements(buf, rval.Len(), func(i int) bool {
+			return other.IsValid() && !sameValue(rval.Index(i), indexOf(other, i))
+		}, ", ", "", "", func(i int) {
+			c.dumpValue(buf, rval.Index(i), indexOf(other, i), depth+1, visited, true)
+		})
+
+		buf.WriteString("}")
+
+	case reflect.Map:
+		if rval.IsNil() {
+			fmt.Fprintf(buf, "%s(nil)", rval.Type())
+			return
+		}
+
+		fmt.Fprintf(buf, "%s{", rval.Type())
+
+		keys := sortedKeys(rval)
+
+		c.dumpElements(buf, len(keys), func(i int) bool {
+			return other.IsValid() && !sameValue(rval.MapIndex(keys[i]), other.MapIndex(keys[i]))
+		}, ", ", "", "", func(i int) {
+			c.dumpValue(buf, keys[i], reflect.Value{}, depth+1, visited, true)
+			buf.WriteString(":")
+			c.dumpValue(buf, rval.MapIndex(keys[i]), mapIndexOf(other, keys[i]), depth+1, visited, true)
+		})
+
+		buf.WriteString("}")
+
+	default:
+		fmt.Fprintf(buf, "%#v", rval)
+
+	}
+}
+
+// visit adds the pointer rval to visited, which is allocated if nil, and returns visited.
+func visit(visited map[uintptr]bool, rval reflect.Value) map[uintptr]bool {
+	if visited == nil {
+		visited = make(map[uintptr]bool)
+	}
+
+	visited[rval.Pointer()] = true
+
+	return visited
+}
+
+// elemOf returns the element of the pointer or interface other, or an invalid value if it's nil.
+func elemOf(other reflect.Value) reflect.Value {
+	if !other.IsValid() || other.IsNil() {
+		return reflect.Value{}
+	}
+
+	return other.Elem()
+}
+
+// fieldOf returns the ith field of the struct other, or an invalid value.
+func fieldOf(other reflect.Value, i int) reflect.Value {
+	if !other.IsValid() {
+		return reflect.Value{}
+	}
+
+	return other.Field(i)
+}
+
+// indexOf returns the ith element of the slice or array other, or an invalid value.
+func indexOf(other reflect.Value, i int) reflect.Value {
+	if !other.IsValid() || i >= other.Len() {
+		return reflect.Value{}
+	}
+
+	return other.Index(i)
+}
+
+// mapIndexOf returns the value of key in the map other, or an invalid value.
+func mapIndexOf(other reflect.Value, key reflect.Value) reflect.Value {
+	if !other.IsValid() {
+		return reflect.Value{}
+	}
+
+	return other.MapIndex(key)
+}
+
+// keyNames sorts keys of maps by their names.
+type keyNames struct {
+	keys  []reflect.Value
+	names []string
+}
+
+func (k keyNames) Len() int {
+	return len(k.keys)
+}
+
+func (k keyNames) Less(i, j int) bool {
+	return k.names[i] < k.names[j]
+}
+
+func (k keyNames) Swap(i, j int) {
+	k.keys[i], k.keys[j] = k.keys[j], k.keys[i]
+	k.names[i], k.names[j] = k.names[j], k.names[i]
+}
@@ -0,0 +1,110 @@
+package gospec
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testingAmount struct {
+	cents int64
+}
+
+type testingOrder struct {
+	ID     string
+	Amount testingAmount
+}
+
+type testingID [4]byte
+
+func (id testingID) String() string {
+	return fmt.Sprintf("%x-%x", id[:2], id[2:])
+}
+
+func TestRegisterFormatter(t *testing.T) {
+	RegisterFormatter(testingAmount{}, func(v interface{}) string {
+		amount := v.(testingAmount)
+
+		return fmt.Sprintf("$%d.%02d", amount.cents/100, amount.cents%100)
+	})
+	defer RegisterFormatter(testingAmount{}, nil)
+
+	exps, acts := toString(testingAmount{1050}, testingAmount{99})
+	Equal(t, "$10.50", exps)
+	Equal(t, "$0.99", acts)
+
+	exps, acts = toString(testingAmount{1050}, 1050)
+	Equal(t, "gospec.testingAmount($10.50)", exps)
+	Equal(t, "int(1050)", acts)
+
+	expected := `--- Expected
++++ Actual
+@@ -2,3 +2,3 @@
+  ID: (string) "order",
+- Amount: $10.50,
++ Amount: $0.99,
+ }
+`
+	Equal(t, expected, diff(testingOrder{"order", testingAmount{1050}}, testingOrder{"order", testingAmount{99}}))
+
+	expected = `--- Expected
++++ Actual
+@@ -1,3 +1,3 @@
+ ([]gospec.testingAmount) (len=2) {
+- $10.50,
++ $0.99,
+  $1.00,
+`
+	Equal(t, expected, diff([]testingAmount{{1050}, {100}}, []testingAmount{{99}, {100}}))
+
+	mockT := new(testingLogger)
+	False(t, NotContains(mockT, []testingAmount{{1050}}, testingAmount{1050}))
+
+	// nested values are formatted too
+	exps, acts = toString(testingOrder{"order", testingAmount{1050}}, testingOrder{"order", testingAmount{99}})
+	Equal(t, `gospec.testingOrder{ID:"order", Amount:$10.50}`, exps)
+	Equal(t, `gospec.testingOrder{ID:"order", Amount:$0.99}`, acts)
+
+	m := map[string][]testingAmount{"b": {{100}}, "a": nil}
+	exps, _ = toString(m, m)
+	Equal(t, `map[string][]gospec.testingAmount{"a":[]gospec.testingAmount(nil), "b":[]gospec.testingAmount{$1.00}}`, exps)
+
+	values := &[]interface{}{testingAmount{1}, nil, 2}
+	exps, _ = toString(values, values)
+	Equal(t, `&[]interface {}{$0.01, interface {}(nil), 2}`, exps)
+
+	rt := new(RecordingT)
+	False(t, NotEqual(rt, testingOrder{"order", testingAmount{1050}}, testingOrder{"order", testingAmount{1050}}))
+	Contains(t, rt.String(), `gospec.testingOrder{ID:"order", Amount:$10.50}`)
+
+	// nil removes the formatter
+	RegisterFormatter(reflect.TypeOf(testingAmount{}), nil)
+
+	exps, _ = toString(testingAmount{1050}, testingAmount{99})
+	Equal(t, "gospec.testingAmount{cents:1050}", exps)
+}
+
+func TestConfig_Stringers(t *testing.T) {
+	id := testingID{0xde, 0xad, 0xbe, 0xef}
+
+	c := DefaultConfig()
+
+	exps, _ := c.toString(id, id)
+	Equal(t, "gospec.testingID{0xde, 0xad, 0xbe, 0xef}", exps)
+
+	c.Stringers = true
+
+	exps, _ = c.toString(id, id)
+	Equal(t, "dead-beef", exps)
+	True(t, strings.Contains(c.diff([]testingID{id}, []testingID{{}}), "- dead-beef,\n+ 0000-0000,\n"))
+
+	// nil receivers of value methods
+	var nilID *testingID
+
+	rt := new(RecordingT)
+	False(t, Equal(WithConfig(rt, func(c *Config) {
+		c.Stringers = true
+	}), &id, nilID))
+	Contains(t, rt.String(), "(*gospec.testingID)(nil)")
+}
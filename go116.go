@@ -0,0 +1,7 @@
+//go:build !go1.16
+// +build !go1.16
+
+package gospec
+
+// gospec requires Go 1.16 or later, which provides io/fs, testing/fstest and os.ReadFile.
+var _ = gospecRequiresGo116OrLater
@@ -3,45 +3,60 @@ package gospec
 import (
 	"fmt"
 	"io"
-	"io/ioutil"
 	"log"
+	"os"
 	"path"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"runtime"
 	"strings"
+	"sync"
 	"unicode"
 	"unicode/utf8"
 
-	"github.com/davecgh/go-spew/spew"
 	"github.com/pmezard/go-difflib/difflib"
 )
 
 var (
-	spewConfig = spew.ConfigState{
-		Indent:                  " ",
-		DisablePointerAddresses: true,
-		DisableCapacities:       true,
-		SortKeys:                true,
-	}
+	workspace     string
+	workspaceOnce sync.Once
 )
 
 // Errorf reports a failure through and return false
 func Errorf(t TestingT, err string, extras ...interface{}) bool {
-	traces, padding := getBacktrace()
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	c := configOf(t)
+
+	frames := getBacktrace(c)
+
+	traces := make([]string, 0, len(frames))
+	for _, frame := range frames {
+		traces = append(traces, formatFrame(frame))
+	}
 
-	output := &testingOutput{}
+	// frames after the first one are aligned with it by the width of the label and a tab, since
+	// formatted frames vary in length with relative paths and funcs
+	output := &testingOutput{config: c}
 	output.Add(labeledOutput{
 		label:   labelErrorTrace,
-		content: strings.Join(traces, labelNewLine+strings.Repeat(" ", padding+1)),
+		content: strings.Join(traces, c.newLine()+strings.Repeat(" ", len(labelErrorTrace)+1)+"\t"),
 	}).Add(labeledOutput{
 		label:   labelError,
 		content: err,
 	})
 
+	var args arguments
+
 	message := ""
 	for _, extra := range extras {
 		switch extra.(type) {
+		case arguments:
+			args = extra.(arguments)
+
 		case labeledOutput:
 			output.Add(extra.(labeledOutput))
 
@@ -76,7 +91,26 @@ func Errorf(t TestingT, err string, extras ...interface{}) bool {
 		})
 	}
 
-	t.Errorf("%s", output)
+	if c.Expressions {
+		if expression := c.describeExpression(output.labels, args); expression != "" {
+			output.Add(labeledOutput{
+				label:   labelExpression,
+				content: expression,
+			})
+		}
+	}
+
+	// elide long values and diffs for display, reporters receive them in full, while elements of
+	// huge values are elided by limits when they are formatted
+	display, _ := output.Truncate(c.Limits, args)
+
+	t.Errorf("%s", display)
+
+	report(t, err, frames, output)
+
+	if c.FailNow {
+		failNow(t)
+	}
 
 	return false
 }
@@ -193,6 +227,10 @@ func IsEmpty(v interface{}) bool {
 // ContainsElement try loop over the list checking if the list includes the element.
 // 	return false if impossible.
 // 	return true if element was found, false otherwise.
+//
+// io.Reader values are searched by streaming if they can be rewound or expose their unread bytes.
+// Other readers, e.g. http.Response.Body, are left unread and reported as not containing the element,
+// use ReaderContains to search them instead.
 func ContainsElement(v, element interface{}) (ok bool) {
 	defer func() {
 		if e := recover(); e != nil {
@@ -239,35 +277,21 @@ func ContainsElement(v, element interface{}) (ok bool) {
 
 	}
 
-	var found bool
-
-	switch v.(type) {
-	case io.Reader:
-		r := v.(io.Reader)
-
-		b, err := ioutil.ReadAll(r)
-		if err != nil {
+	if r, ok := v.(io.Reader); ok {
+		if !searchable(r) {
+			log.Printf("[WARN] ContainsElement(%T, %v): io.Reader cannot be searched without consuming it, use ReaderContains instead\n", r, element)
 			return false
 		}
 
-		found = strings.Contains(string(b), elementValue.String())
-
-		switch v.(type) {
-		case io.Seeker:
-			seeker := v.(io.Seeker)
-			seeker.Seek(0, 0)
-
-		case io.Writer:
-			writer := v.(io.Writer)
-			writer.Write(b)
-
-		default:
-			log.Printf("[WARN] ContainsElement(%T, %v): CANNOT reset io.Reader for next read\n", v, element)
-
+		found, err := containsReader(r, element)
+		if err != nil {
+			log.Printf("[WARN] ContainsElement(%T, %v): %v\n", r, element, err)
 		}
+
+		return found[0] && err == nil
 	}
 
-	return found
+	return false
 }
 
 // Stolen from the `go test` tool.
@@ -307,18 +331,41 @@ func recovery(f PanicRecover) (isPanic bool, err interface{}) {
 // diff returns a diff of values of the same type and it's type MUST be a struct, map, slice or array.
 // Otherwise it returns an empty string.
 func diff(expected, actual interface{}) string {
+	c := GetConfig()
+
+	return c.diff(expected, actual)
+}
+
+// diff returns a diff of values formatted by the config.
+func (c *Config) diff(expected, actual interface{}) string {
 	if expected == nil || actual == nil {
 		if expected == actual {
 			return ""
 		}
 
-		return fmt.Sprintf("--- %T(%v)\n+++ %T(%v)\n\n", expected, expected, actual, actual)
+		return fmt.Sprintf("--- %T(%s)\n+++ %T(%s)\n\n", expected, c.sprint("%v", expected), actual, c.sprint("%v", actual))
 	}
 
 	et, ek := getTypeAndKind(expected)
 	at, _ := getTypeAndKind(actual)
 	if et != at {
-		return fmt.Sprintf("--- %T(%v)\n+++ %T(%v)\n\n", expected, expected, actual, actual)
+		return fmt.Sprintf("--- %T(%s)\n+++ %T(%s)\n\n", expected, c.sprint("%v", expected), actual, c.sprint("%v", actual))
+	}
+
+	formattable := c.formattable(reflect.TypeOf(expected))
+
+	// diff text line by line instead of its spew dump
+	if formattable {
+		// ignore
+	} else if exps, acts, ok := toText(expected, actual); ok {
+		if diff, ok := diffText(exps, acts, c); ok {
+			return diff
+		}
+	} else if exps, ok := bytesOf(expected); ok {
+		// binary data is diffed by hex dump
+		if acts, ok := bytesOf(actual); ok {
+			return diffBytes(exps, acts, c.HexWindow)
+		}
 	}
 
 	switch ek {
@@ -330,11 +377,19 @@ func diff(expected, actual interface{}) string {
 			return ""
 		}
 
-		return fmt.Sprintf("--- %T(%v)\n+++ %T(%v)\n\n", expected, expected, actual, actual)
+		return fmt.Sprintf("--- %T(%s)\n+++ %T(%s)\n\n", expected, c.sprint("%v", expected), actual, c.sprint("%v", actual))
 	}
 
-	exps := spewConfig.Sdump(expected)
-	acts := spewConfig.Sdump(actual)
+	// formatters and limits are ignored by spew, so such values are dumped by config, which elides
+	// elements exceeding limits rather than diffing all of them
+	var exps, acts string
+	if formattable || c.exceedsLimits(reflect.ValueOf(expected), 0, nil) || c.exceedsLimits(reflect.ValueOf(actual), 0, nil) {
+		exps = c.dump(expected, actual)
+		acts = c.dump(actual, expected)
+	} else {
+		exps = c.spew().Sdump(expected)
+		acts = c.spew().Sdump(actual)
+	}
 
 	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
 		A:        difflib.SplitLines(exps),
@@ -343,7 +398,7 @@ func diff(expected, actual interface{}) string {
 		FromDate: "",
 		ToFile:   "Actual",
 		ToDate:   "",
-		Context:  1,
+		Context:  c.DiffContext,
 	})
 
 	return diff
@@ -383,40 +438,6 @@ func tryLen(v interface{}) (length int, ok bool) {
 	return
 }
 
-func toFloat(v interface{}) (float64, bool) {
-	var f64 float64
-	ok := true
-
-	switch t := v.(type) {
-	case uint8:
-		f64 = float64(t)
-	case uint16:
-		f64 = float64(t)
-	case uint32:
-		f64 = float64(t)
-	case uint64:
-		f64 = float64(t)
-	case int:
-		f64 = float64(t)
-	case int8:
-		f64 = float64(t)
-	case int16:
-		f64 = float64(t)
-	case int32:
-		f64 = float64(t)
-	case int64:
-		f64 = float64(t)
-	case float32:
-		f64 = float64(t)
-	case float64:
-		f64 = float64(t)
-	default:
-		ok = false
-	}
-
-	return f64, ok
-}
-
 // toString takes two values of arbitrary types and returns string
 // representations appropriate to be presented to the user.
 //
@@ -424,9 +445,16 @@ func toFloat(v interface{}) (float64, bool) {
 // with the type name, and the value will be enclosed in parenthesis similar
 // to a type conversion in the Go grammar.
 func toString(expected, actual interface{}) (exps, acts string) {
+	c := GetConfig()
+
+	return c.toString(expected, actual)
+}
+
+// toString returns string representations of values with registered formatters applied.
+func (c *Config) toString(expected, actual interface{}) (exps, acts string) {
 	if reflect.TypeOf(expected) == reflect.TypeOf(actual) {
-		exps = fmt.Sprintf("%#v", expected)
-		acts = fmt.Sprintf("%#v", actual)
+		exps = c.sprintOf("%#v", expected, actual)
+		acts = c.sprintOf("%#v", actual, expected)
 
 		return
 	}
@@ -457,7 +485,7 @@ func toString(expected, actual interface{}) (exps, acts string) {
 		default:
 			if expval.IsValid() {
 				if actval.IsValid() {
-					exps = fmt.Sprintf("%T(%#v)", expected, expected)
+					exps = fmt.Sprintf("%T(%s)", expected, c.sprint("%#v", expected))
 				} else {
 					exps = expval.String()
 					if exps == "" {
@@ -493,7 +521,7 @@ func toString(expected, actual interface{}) (exps, acts string) {
 		default:
 			if actval.IsValid() {
 				if expval.IsValid() {
-					acts = fmt.Sprintf("%T(%#v)", actual, actual)
+					acts = fmt.Sprintf("%T(%s)", actual, c.sprint("%#v", actual))
 				} else {
 					acts = actval.String()
 					if acts == "" {
@@ -534,59 +562,122 @@ func getWhitespace() string {
 	return strings.Repeat(" ", len(fmt.Sprintf("%s:%d:        ", filename, line)))
 }
 
+// getWorkspace returns the root dir which file paths of failure output are relative to.
+// It's $GITHUB_WORKSPACE if present, or the nearest dir containing go.mod of the working dir.
+func getWorkspace() string {
+	workspaceOnce.Do(func() {
+		if dir := os.Getenv("GITHUB_WORKSPACE"); dir != "" {
+			workspace = dir
+			return
+		}
+
+		dir, err := os.Getwd()
+		if err != nil {
+			return
+		}
+
+		workspace = dir
+		for {
+			if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+				workspace = dir
+				return
+			}
+
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				return
+			}
+
+			dir = parent
+		}
+	})
+
+	return workspace
+}
+
+// getRelativePath returns path of the file relative to the workspace if possible.
+func getRelativePath(file string) string {
+	root := getWorkspace()
+	if root == "" {
+		return file
+	}
+
+	rel, err := filepath.Rel(root, filepath.FromSlash(file))
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return file
+	}
+
+	return filepath.ToSlash(rel)
+}
+
 // getBacktrace is necessary because the assert functions use the testing object
 // internally, causing it to print the file:line of the assert method, rather than where
 // the problem actually occurred in calling code.
 //
-// getBacktrace returns an array of strings containing the file and line number
+// getBacktrace returns an array of frames containing the full file path and line number
 // of each stack frame leading from the current test to the assert call that failed.
-func getBacktrace() (callers []string, longestFile int) {
-	for i := 1; ; i++ {
-		pc, file, line, ok := runtime.Caller(i)
-		if !ok {
-			// The breaks below failed to terminate the loop, and we ran off the
-			// end of the call stack.
-			break
-		}
+// Frames of the package, and frames hidden by TraceSkipDirs and TraceSkipFuncs of the config
+// are skipped, and at most TraceDepth frames are returned if it's positive.
+func getBacktrace(c *Config) (callers []TraceFrame) {
+	pkgPath := reflect.TypeOf(labeledOutput{}).PkgPath()
 
-		// This is a huge edge case, but it will panic if this is the case, see #180
-		if file == "<autogenerated>" {
-			break
-		}
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
 
-		f := runtime.FuncForPC(pc)
-		if f == nil {
+		// This is a huge edge case, but it will panic if this is the case, see #180
+		if frame.File == "<autogenerated>" || frame.Function == "" {
 			break
 		}
-		name := f.Name()
+		name := frame.Function
 
 		// testing.tRunner is the standard library function that calls
 		// tests. Subtests are called directly by tRunner, without going through
 		// the Test/Benchmark/Example function that contains the t.Run calls, so
 		// with subtests we should break when we hit tRunner, without adding it
-		// to the list of callers.
-		if name == "testing.tRunner" {
+		// to the list of callers. runtime.goexit is the bottom of goroutines started
+		// by tests, e.g. the goroutine of RecordingT.Run.
+		if name == "testing.tRunner" || name == "runtime.goexit" {
 			break
 		}
 
-		parts := strings.Split(file, "/")
-		dir := parts[len(parts)-2]
-		file = parts[len(parts)-1]
-
-		if !strings.HasSuffix(file, "_test.go") && len(file) > longestFile {
-			longestFile = len(file)
+		dir := path.Base(path.Dir(frame.File))
+		filename := path.Base(frame.File)
+		isTestFile := strings.HasSuffix(filename, "_test.go")
+
+		// frames of test files are always kept unless they're registered as helpers, and frames
+		// of runtime and reflect are skipped, e.g. runtime.gopanic of recovered panics
+		skipped := !isTestFile && (strings.HasPrefix(name, pkgPath+".") ||
+			strings.HasPrefix(name, "runtime.") || strings.HasPrefix(name, "reflect."))
+		if !isTestFile {
+			for _, skipDir := range c.TraceSkipDirs {
+				if dir == skipDir {
+					skipped = true
+					break
+				}
+			}
+		}
+		for _, pattern := range c.TraceSkipFuncs {
+			if matchFunc(name, pattern) {
+				skipped = true
+				break
+			}
 		}
 
-		if (dir != "assert" && dir != "mock" && dir != "require") ||
-			file == "assertions_test.go" ||
-			file == "expectations_test.go" {
-			callers = append(callers, fmt.Sprintf("%s:%d", file, line))
+		if !skipped && (c.TraceDepth <= 0 || len(callers) < c.TraceDepth) {
+			callers = append(callers, TraceFrame{
+				File:     frame.File,
+				Line:     frame.Line,
+				Function: name,
+			})
 		}
 
 		// Drop the package
 		segments := strings.Split(name, ".")
 		name = segments[len(segments)-1]
-		if isTest(name, "Test") ||
+		if !more ||
+			isTest(name, "Test") ||
 			isTest(name, "Benchmark") ||
 			isTest(name, "Example") {
 			break
@@ -596,6 +687,35 @@ func getBacktrace() (callers []string, longestFile int) {
 	return
 }
 
+// matchFunc returns whether the full name of func matches the pattern, which is a package path,
+// a func name or a glob of path.Match, e.g. "github.com/acme/testutil", "github.com/acme/testutil.Check"
+// and "github.com/acme/*.Check".
+func matchFunc(name, pattern string) bool {
+	if name == pattern || strings.HasPrefix(name, pattern+".") {
+		return true
+	}
+
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// formatFrame returns the frame as a module-relative path with line number and the func name.
+func formatFrame(frame TraceFrame) string {
+	if frame.Function == "" {
+		return fmt.Sprintf("%s:%d", getRelativePath(frame.File), frame.Line)
+	}
+
+	return fmt.Sprintf("%s:%d %s", getRelativePath(frame.File), frame.Line, path.Base(frame.Function))
+}
+
+// getAssertionName returns the name of the outermost exported func of the package in the
+// call stack, which is the assertion invoked by test code.
+func getAssertionName() (name string) {
+	assertion, _, _ := getCallSite()
+
+	return strings.TrimPrefix(assertion.Function, reflect.TypeOf(labeledOutput{}).PkgPath()+".")
+}
+
 func formatExtras(extras ...interface{}) string {
 	message := ""
 	if len(extras) > 0 {
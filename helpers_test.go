@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -204,17 +205,16 @@ func TestContainsElement(t *testing.T) {
 	True(t, ContainsElement(rw, "world"))
 	False(t, ContainsElement(rw, "Foo Bar"))
 
+	// unseekable readers are left unread
 	r := &testingReader{
 		s: "Hello, world!",
 	}
-	True(t, ContainsElement(r, "Hello"))
-	False(t, ContainsElement(r, "world"))
-
-	r = &testingReader{
-		s: "Hello, world!",
-	}
-	True(t, ContainsElement(r, "world"))
 	False(t, ContainsElement(r, "Hello"))
+	Equal(t, int64(0), r.i)
+
+	data, err := io.ReadAll(r)
+	NotError(t, err)
+	Equal(t, "Hello, world!", string(data))
 }
 
 func Test_recovery(t *testing.T) {
@@ -465,3 +465,125 @@ func Test_toString(t *testing.T) {
 
 	toString(nil, nil)
 }
+
+type testingHelperT struct {
+	RecordingT
+
+	helpers int
+}
+
+func (t *testingHelperT) Helper() {
+	t.helpers++
+}
+
+func testingAssertPositive(t TestingT, n int) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	return True(t, n > 0)
+}
+
+func Test_getBacktrace(t *testing.T) {
+	c := DefaultConfig()
+
+	var frames []TraceFrame
+	testingAssertFrames := func() {
+		frames = getBacktrace(&c)
+	}
+
+	testingAssertFrames()
+	if Len(t, frames, 2) {
+		True(t, strings.HasSuffix(frames[0].Function, "Test_getBacktrace.func1"))
+		Equal(t, "github.com/dolab/gospec.Test_getBacktrace", frames[1].Function)
+		True(t, strings.HasSuffix(formatFrame(frames[1]), "helpers_test.go:"+strconv.Itoa(frames[1].Line)+" gospec.Test_getBacktrace"))
+	}
+
+	c.TraceSkipFuncs = []string{"github.com/dolab/gospec.Test_getBacktrace.*"}
+
+	testingAssertFrames()
+	if Len(t, frames, 1) {
+		Equal(t, "github.com/dolab/gospec.Test_getBacktrace", frames[0].Function)
+	}
+
+	c.TraceSkipFuncs = nil
+	c.TraceDepth = 1
+
+	testingAssertFrames()
+	Len(t, frames, 1)
+}
+
+func TestErrorfWithTraces(t *testing.T) {
+	rt := NewRecordingT("TestErrorfWithTraces")
+
+	testingAssertNested := func(t TestingT) {
+		True(t, false)
+	}
+	rt.Run(func(t TestingT) {
+		testingAssertNested(t)
+	})
+
+	records, outputs := rt.Records(), rt.Outputs()
+	if Len(t, records, 1) && Len(t, outputs, 1) && Len(t, records[0].Trace, 2) {
+		// frames after the first one are aligned with it regardless of their lengths
+		Contains(t, outputs[0], labelErrorTrace+":\t"+records[0].Trace[0]+"\n\r\t \t\r\t            \t"+records[0].Trace[1]+"\n")
+	}
+}
+
+func TestRegisterHelper(t *testing.T) {
+	origin := GetConfig()
+	defer SetConfig(origin)
+
+	functionsOf := func(record *FailureRecord) []string {
+		var functions []string
+		for _, frame := range record.Frames {
+			functions = append(functions, frame.Function)
+		}
+
+		return functions
+	}
+
+	mockT := new(testingHelperT)
+
+	False(t, testingAssertPositive(mockT, 0))
+	True(t, mockT.helpers >= 3, "helper, True and Errorf should be marked")
+
+	RegisterHelper("github.com/dolab/gospec.testingAssertPositive")
+
+	False(t, testingAssertPositive(WithConfig(mockT), 0))
+
+	records := mockT.Records()
+	if Len(t, records, 2) {
+		Contains(t, functionsOf(records[0]), "github.com/dolab/gospec.testingAssertPositive")
+		NotContains(t, functionsOf(records[1]), "github.com/dolab/gospec.testingAssertPositive")
+		Contains(t, functionsOf(records[1]), "github.com/dolab/gospec.TestRegisterHelper")
+	}
+}
+
+func Test_matchFunc(t *testing.T) {
+	trueCases := []struct {
+		name, pattern string
+	}{
+		{"github.com/acme/testutil.Check", "github.com/acme/testutil"},
+		{"github.com/acme/testutil.Check", "github.com/acme/testutil.Check"},
+		{"github.com/acme/testutil.Check.func1", "github.com/acme/testutil.Check"},
+		{"github.com/acme/testutil.(*Suite).Check", "github.com/acme/*.(*Suite).Check"},
+		{"github.com/acme/testutil.Check", "github.com/acme/testutil.*"},
+	}
+
+	for i, tc := range trueCases {
+		True(t, matchFunc(tc.name, tc.pattern), "matchFunc should return true for trueCases(%d)", i)
+	}
+
+	falseCases := []struct {
+		name, pattern string
+	}{
+		{"github.com/acme/testutil2.Check", "github.com/acme/testutil"},
+		{"github.com/acme/testutil.CheckAll", "github.com/acme/testutil.Check"},
+		{"github.com/acme/testutil/sub.Check", "github.com/acme/*.Check"},
+	}
+
+	for i, fc := range falseCases {
+		False(t, matchFunc(fc.name, fc.pattern), "matchFunc should return false for falseCases(%d)", i)
+	}
+}
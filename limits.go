@@ -0,0 +1,363 @@
+package gospec
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const labelFullOutput = "Full Output"
+
+// Limits defines limits of values and diffs in failure output, zero means no limit.
+// Values and diffs exceeding the limits are elided with markers like `... 49990 more elements`.
+type Limits struct {
+	// MaxDepth limits the nesting depth of composite values, which is applied while formatting them.
+	MaxDepth int
+	// MaxElements limits the number of elements of slices, arrays, maps and structs, which is applied
+	// while formatting them. Elements differing between expected and received values are kept,
+	// up to another MaxElements of them.
+	MaxElements int
+	// MaxStringLen limits the number of characters of values and lines of other output.
+	MaxStringLen int
+	// MaxDiffLines limits the number of lines of diffs. Hunk headers and changed lines are always
+	// kept, so all differing regions are visible, and context lines are elided instead.
+	MaxDiffLines int
+	// ArtifactDir enables writing the full output to a temp file in the dir, which is referenced
+	// in the failure output. Values of arguments of the assertion are written without limits too.
+	ArtifactDir string
+}
+
+// SetLimits changes limits of values and diffs in failure output.
+func SetLimits(l Limits) {
+	UpdateConfig(func(c *Config) {
+		c.Limits = l
+	})
+}
+
+// GetLimits returns current limits of values and diffs in failure output.
+func GetLimits() Limits {
+	return GetConfig().Limits
+}
+
+// valueLabels are labels of values formatted as Go syntax, which are truncated as a whole.
+var valueLabels = map[string]bool{
+	"-expected": true,
+	"+expected": true,
+	"-received": true,
+	"+received": true,
+	"-element":  true,
+	"+element":  true,
+	"-key":      true,
+	"-value":    true,
+	"+value":    true,
+	"-list":     true,
+	"+subset":   true,
+}
+
+// Truncate returns a copy of output with contents elided by limits, and whether any content is elided.
+// Elements and levels of values are elided while formatting them, so only their lengths are limited here.
+// If ArtifactDir of limits is present, the full output with args is written to a temp file in the dir
+// and referenced by a "Full Output" label.
+func (output *testingOutput) Truncate(l Limits, args arguments) (*testingOutput, bool) {
+	truncated := &testingOutput{
+		padding: output.padding,
+		config:  output.config,
+	}
+
+	elided := false
+	for _, label := range output.labels {
+		var ok bool
+
+		switch {
+		case label.label == labelErrorTrace || label.label == labelError || label.label == labelMessages:
+			// ignore
+
+		case label.label == labelDiff:
+			label.content, ok = truncateDiff(label.content, l)
+
+		case valueLabels[label.label]:
+			label.content, ok = truncateValue(label.content, l)
+
+		default:
+			label.content, ok = truncateLines(label.content, l)
+
+		}
+
+		elided = elided || ok
+		truncated.Add(label)
+	}
+
+	if elided && l.ArtifactDir != "" {
+		if filename, err := output.writeArtifact(l.ArtifactDir, args); err == nil {
+			truncated.Add(labeledOutput{
+				label:   labelFullOutput,
+				content: filename,
+			})
+		} else {
+			truncated.Add(labeledOutput{
+				label:   labelFullOutput,
+				content: fmt.Sprintf("<cannot write artifact: %v>", err),
+			})
+		}
+	}
+
+	return truncated, elided
+}
+
+// writeArtifact writes all labels of output, and values of args formatted without limits, to a temp
+// file in dir, and returns its name.
+func (output *testingOutput) writeArtifact(dir string, args arguments) (string, error) {
+	file, err := os.CreateTemp(dir, "gospec-*.txt")
+	if err != nil {
+		return "", err
+	}
+
+	for _, label := range output.labels {
+		fmt.Fprintf(file, "%s:\n%s\n\n", label.label, strings.Replace(label.content, output.newLine(), "\n", -1))
+	}
+
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	full := output.getConfig().clone()
+	full.Limits = Limits{}
+
+	for _, name := range names {
+		fmt.Fprintf(file, "%s:\n%s\n\n", name, full.sprint("%#v", args[name]))
+	}
+
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	return file.Name(), nil
+}
+
+// truncateValue truncates a formatted value to MaxStringLen characters.
+func truncateValue(s string, l Limits) (string, bool) {
+	if l.MaxStringLen > 0 && utf8.RuneCountInString(s) > l.MaxStringLen {
+		runes := []rune(s)
+
+		return fmt.Sprintf("%s ... %d more characters", string(runes[:l.MaxStringLen]), len(runes)-l.MaxStringLen), true
+	}
+
+	return s, false
+}
+
+// truncateLines truncates each line of s longer than MaxStringLen, keeping the structure of lines,
+// e.g. diffs of labels other than Diff.
+func truncateLines(s string, l Limits) (string, bool) {
+	if l.MaxStringLen <= 0 {
+		return s, false
+	}
+
+	lines := strings.Split(s, "\n")
+	elided := false
+	for i, line := range lines {
+		if utf8.RuneCountInString(line) > l.MaxStringLen {
+			lines[i] = elideLine(line, 0, l.MaxStringLen)
+			elided = true
+		}
+	}
+
+	if !elided {
+		return s, false
+	}
+
+	return strings.Join(lines, "\n"), true
+}
+
+// truncateDiff truncates lines of diff longer than MaxStringLen around their first differing
+// characters, and elides lines beyond MaxDiffLines except headers of files and hunks.
+func truncateDiff(diff string, l Limits) (string, bool) {
+	if diff == "" {
+		return diff, false
+	}
+
+	lines := strings.Split(strings.TrimSuffix(diff, "\n"), "\n")
+	elided := false
+
+	if l.MaxStringLen > 0 {
+		inHunk := false
+
+		for i := 0; i < len(lines); {
+			line := lines[i]
+
+			if strings.HasPrefix(line, "@@") {
+				inHunk = true
+			}
+
+			if !inHunk || !(strings.HasPrefix(line, "-") || strings.HasPrefix(line, "+")) {
+				if utf8.RuneCountInString(line) > l.MaxStringLen {
+					lines[i] = elideLine(line, 0, l.MaxStringLen)
+					elided = true
+				}
+
+				i++
+				continue
+			}
+
+			removed, added, next := collectChanges(lines, i)
+			for j, s := range removed {
+				pivot := 0
+				if j < len(added) {
+					pivot = firstDifference(s, added[j])
+				}
+
+				if utf8.RuneCountInString(s) > l.MaxStringLen-1 {
+					lines[i+j] = "-" + elideLine(s, pivot, l.MaxStringLen-1)
+					elided = true
+				}
+			}
+
+			for j, s := range added {
+				pivot := 0
+				if j < len(removed) {
+					pivot = firstDifference(removed[j], s)
+				}
+
+				if utf8.RuneCountInString(s) > l.MaxStringLen-1 {
+					lines[i+len(removed)+j] = "+" + elideLine(s, pivot, l.MaxStringLen-1)
+					elided = true
+				}
+			}
+
+			i = next
+		}
+	}
+
+	if l.MaxDiffLines > 0 && len(lines) > l.MaxDiffLines {
+		budget := l.MaxDiffLines
+
+		// headers and changed lines are always kept, so all differing regions are visible
+		keep := make([]bool, len(lines))
+		changed := make([]bool, len(lines))
+		inHunk := false
+		for i, line := range lines {
+			if strings.HasPrefix(line, "@@") {
+				inHunk = true
+			}
+
+			switch {
+			case strings.HasPrefix(line, "@@") || (i < 2 && (strings.HasPrefix(line, "---") || strings.HasPrefix(line, "+++"))):
+				keep[i] = true
+				budget--
+
+			case inHunk && (strings.HasPrefix(line, "-") || strings.HasPrefix(line, "+")):
+				keep[i] = true
+				changed[i] = true
+				budget--
+
+			}
+		}
+
+		// context lines closest to changes are kept within the rest of budget
+		distances := make([]int, len(lines))
+		for i, last := 0, -len(lines); i < len(lines); i++ {
+			if changed[i] {
+				last = i
+			}
+			distances[i] = i - last
+		}
+		for i, last := len(lines)-1, 2*len(lines); i >= 0; i-- {
+			if changed[i] {
+				last = i
+			}
+			if last-i < distances[i] {
+				distances[i] = last - i
+			}
+		}
+
+		contexts := make([]int, 0, len(lines))
+		for i := range lines {
+			if !keep[i] {
+				contexts = append(contexts, i)
+			}
+		}
+		sort.SliceStable(contexts, func(i, j int) bool {
+			return distances[contexts[i]] < distances[contexts[j]]
+		})
+
+		for _, i := range contexts {
+			if budget <= 0 {
+				break
+			}
+
+			keep[i] = true
+			budget--
+		}
+
+		kept := make([]string, 0, l.MaxDiffLines+1)
+		for i := 0; i < len(lines); {
+			if keep[i] {
+				kept = append(kept, lines[i])
+
+				i++
+				continue
+			}
+
+			n := 0
+			for ; i < len(lines) && !keep[i]; i++ {
+				n++
+			}
+
+			kept = append(kept, fmt.Sprintf(" ... %d more lines", n))
+		}
+
+		lines = kept
+		elided = true
+	}
+
+	if !elided {
+		return diff, false
+	}
+
+	return strings.Join(lines, "\n") + "\n", true
+}
+
+// firstDifference returns the index of the first rune differing between a and b.
+func firstDifference(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	i := 0
+	for i < len(ra) && i < len(rb) && ra[i] == rb[i] {
+		i++
+	}
+
+	return i
+}
+
+// elideLine returns a window of max runes of line around the rune at pivot, with the elided
+// parts replaced by "…".
+func elideLine(line string, pivot, max int) string {
+	runes := []rune(line)
+	if len(runes) <= max {
+		return line
+	}
+
+	start := pivot - max/4
+	if start < 0 {
+		start = 0
+	}
+
+	end := start + max
+	if end > len(runes) {
+		end = len(runes)
+		start = end - max
+	}
+
+	s := string(runes[start:end])
+	if start > 0 {
+		s = "…" + s
+	}
+	if end < len(runes) {
+		s += "…"
+	}
+
+	return s
+}
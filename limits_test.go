@@ -0,0 +1,257 @@
+package gospec
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLimitsWhileFormatting(t *testing.T) {
+	c := DefaultConfig()
+	c.Limits = Limits{MaxElements: 10}
+
+	values := make([]int, 50000)
+
+	exps, _ := c.toString(values, values)
+	Equal(t, "[]int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, ... 49990 more elements}", exps)
+
+	exps, _ = c.toString([]string{"a,b", "c}", "d"}, []string{"a,b", "c}", "d"})
+	Equal(t, `[]string{"a,b", "c}", "d"}`, exps)
+
+	c.Limits = Limits{MaxElements: 2}
+
+	exps, _ = c.toString([][]int{{1, 2, 3}, {4}}, [][]int{{1, 2, 3}, {4}})
+	Equal(t, `[][]int{[]int{1, 2, ... 1 more elements}, []int{4}}`, exps)
+
+	type node struct {
+		Next *node
+	}
+
+	c.Limits = Limits{MaxDepth: 2}
+
+	list := &node{Next: &node{Next: &node{}}}
+	exps, _ = c.toString(list, list)
+	Equal(t, `&gospec.node{Next:&gospec.node{Next:&gospec.node{...}}}`, exps)
+
+	c.Limits = Limits{MaxElements: 2, MaxDepth: 2}
+
+	exps, _ = c.toString([]int{1, 2}, []int{1, 2})
+	Equal(t, `[]int{1, 2}`, exps)
+}
+
+func TestLimitsWithDifferences(t *testing.T) {
+	c := DefaultConfig()
+	c.Limits = Limits{MaxElements: 3}
+
+	expected := make([]int, 1000)
+	actual := make([]int, 1000)
+	actual[500] = 1
+
+	exps, acts := c.toString(expected, actual)
+	Equal(t, "[]int{0, 0, 0, ... 497 more elements, 0, ... 499 more elements}", exps)
+	Equal(t, "[]int{0, 0, 0, ... 497 more elements, 1, ... 499 more elements}", acts)
+
+	// diffs of dumps elided
+	Equal(t, `--- Expected
++++ Actual
+@@ -5,3 +5,3 @@
+  ... 497 more elements,
+- (int) 0,
++ (int) 1,
+  ... 499 more elements,
+`, c.diff(expected, actual))
+
+	// levels containing differences are kept
+	c.Limits = Limits{MaxDepth: 1}
+
+	exps, acts = c.toString([][]int{{1}, {2, 3}}, [][]int{{1}, {2, 4}})
+	Equal(t, `[][]int{[]int{...}, []int{2, 3}}`, exps)
+	Equal(t, `[][]int{[]int{...}, []int{2, 4}}`, acts)
+}
+
+func TestLimitsWithCycles(t *testing.T) {
+	c := DefaultConfig()
+	c.Limits = Limits{MaxElements: 10, MaxDepth: 5}
+
+	// pointer cycle
+	type pointer *pointer
+
+	var p pointer
+	p = &p
+
+	NotPanics(t, func() {
+		c.toString(p, p)
+	})
+	Equal(t, "&&<already shown>\n", c.dump(p, nil))
+
+	// interface cycle
+	var x interface{}
+	x = &x
+
+	NotPanics(t, func() {
+		c.toString(x, 1)
+	})
+	Equal(t, "&&<already shown>\n", c.dump(x, nil))
+
+	rt := NewRecordingT("TestLimitsWithCycles")
+	rt.Run(func(rt TestingT) {
+		Equal(WithConfig(rt, func(rc *Config) {
+			rc.Limits = c.Limits
+		}), x, 1)
+	})
+	True(t, rt.Failed())
+}
+
+func TestLimitsWithUnexportedMaps(t *testing.T) {
+	type entries struct {
+		m map[int]int
+	}
+
+	expected, actual := entries{m: map[int]int{}}, entries{m: map[int]int{}}
+	for i := 0; i < 200; i++ {
+		expected.m[i] = i
+		actual.m[i] = i
+	}
+	actual.m[100] = -1
+
+	for _, limits := range []Limits{DefaultConfig().Limits, {MaxElements: 1}} {
+		rt := NewRecordingT("TestLimitsWithUnexportedMaps")
+		rt.Run(func(rt TestingT) {
+			rc := WithConfig(rt, func(rc *Config) {
+				rc.Limits = limits
+			})
+
+			Equal(rc, expected, actual)
+			NotEqual(rc, expected, expected)
+		})
+
+		records := rt.Records()
+		if Len(t, records, 2, rt.String()) {
+			Equal(t, "Equal", records[0].Assertion)
+			Contains(t, records[0].Diff, "(int) 100: (int) -1")
+			Equal(t, "NotEqual", records[1].Assertion)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	output := &testingOutput{}
+	output.Add(labeledOutput{
+		label:   "+received",
+		content: `"` + strings.Repeat("x", 20) + `"`,
+	}).Add(labeledOutput{
+		label:   "Mismatch",
+		content: "-  " + strings.Repeat("a", 20) + "\n+  " + strings.Repeat("b", 20),
+	})
+
+	truncated, ok := output.Truncate(Limits{MaxStringLen: 10}, nil)
+	if True(t, ok) && Len(t, truncated.labels, 2) {
+		Equal(t, `"xxxxxxxxx ... 12 more characters`, truncated.labels[0].content)
+
+		// lines of other labels are kept
+		Equal(t, "-  aaaaaaa…\n+  bbbbbbb…", truncated.labels[1].content)
+	}
+}
+
+func Test_truncateDiff(t *testing.T) {
+	expected := make([]string, 100)
+	actual := make([]string, 100)
+	for i := range expected {
+		expected[i] = fmt.Sprintf("line %d", i)
+		actual[i] = fmt.Sprintf("line %d", i)
+	}
+	actual[10] = "changed 10"
+	actual[50] = "changed 50"
+	actual[90] = "changed 90"
+
+	unified := diff(strings.Join(expected, "\n"), strings.Join(actual, "\n"))
+
+	s, ok := truncateDiff(unified, Limits{MaxDiffLines: 10})
+	True(t, ok)
+	Equal(t, `--- Expected
++++ Actual
+@@ -10,3 +10,3 @@
+ ... 1 more lines
+-line 10
++changed 10
+ ... 1 more lines
+@@ -50,3 +50,3 @@
+ ... 1 more lines
+-line 50
++changed 50
+ ... 1 more lines
+@@ -90,3 +90,3 @@
+ ... 1 more lines
+-line 90
++changed 90
+ ... 1 more lines
+`, s)
+
+	// context lines closest to changes are kept within the budget
+	s, ok = truncateDiff(unified, Limits{MaxDiffLines: 13})
+	True(t, ok)
+	Equal(t, `--- Expected
++++ Actual
+@@ -10,3 +10,3 @@
+ line 9
+-line 10
++changed 10
+ line 11
+@@ -50,3 +50,3 @@
+ ... 1 more lines
+-line 50
++changed 50
+ ... 1 more lines
+@@ -90,3 +90,3 @@
+ ... 1 more lines
+-line 90
++changed 90
+ ... 1 more lines
+`, s)
+
+	line := strings.Repeat("a", 100) + "b" + strings.Repeat("a", 100)
+	s, ok = truncateDiff(diff(line+"\n", strings.Replace(line, "b", "c", 1)+"\n"), Limits{MaxStringLen: 21})
+	True(t, ok)
+	Equal(t, `--- Expected
++++ Actual
+@@ -1,2 +1,2 @@
+-…aaaaabaaaaaaaaaaaaaa…
++…aaaaacaaaaaaaaaaaaaa…
+ 
+`, s)
+
+	s, ok = truncateDiff(unified, Limits{})
+	False(t, ok)
+	Equal(t, unified, s)
+}
+
+func TestLimitsWithArtifact(t *testing.T) {
+	dir, err := os.MkdirTemp("", "gospec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	values := make([]int, 1000)
+
+	output := &testingOutput{}
+	output.Add(labeledOutput{
+		label:   "+received",
+		content: "[]int{0, 0, 0, ... 997 more elements}",
+	})
+
+	truncated, ok := output.Truncate(Limits{MaxStringLen: 10, ArtifactDir: dir}, arguments{"actual": values})
+	if !True(t, ok) || !Len(t, truncated.labels, 2) {
+		return
+	}
+
+	Equal(t, "[]int{0, 0 ... 27 more characters", truncated.labels[0].content)
+	Equal(t, labelFullOutput, truncated.labels[1].label)
+
+	data, err := os.ReadFile(truncated.labels[1].content)
+	if NotError(t, err) {
+		Contains(t, string(data), output.labels[0].content)
+		Contains(t, string(data), "actual:\n"+fmt.Sprintf("%#v", values))
+	}
+}
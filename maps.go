@@ -0,0 +1,536 @@
+package gospec
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxClosestKeys is the max number of closest keys reported for a missing key.
+const maxClosestKeys = 3
+
+// assertMap checks m is a map and returns its value.
+func assertMap(t TestingT, m interface{}, extras ...interface{}) (reflect.Value, bool) {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	rval := reflect.ValueOf(m)
+	if rval.Kind() != reflect.Map {
+		_, acts := configOf(t).toString(nil, m)
+
+		return rval, Errorf(t, "Parameter must be a map", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "+received",
+				content: acts,
+			},
+		}, arguments{
+			"m": m,
+		})
+	}
+
+	return rval, true
+}
+
+// assertKey checks key is comparable, which can be a key of maps.
+func assertKey(t TestingT, key interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	if err := checkKey(key); err != nil {
+		ks, _ := configOf(t).toString(key, key)
+
+		return Errorf(t, err.Error(), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-key",
+				content: ks,
+			},
+		}, arguments{
+			"key": key,
+		})
+	}
+
+	return true
+}
+
+// checkKey returns an error if key is not comparable, which would panic as a key of maps.
+func checkKey(key interface{}) error {
+	if kval := reflect.ValueOf(key); kval.IsValid() && !kval.Type().Comparable() {
+		return fmt.Errorf("Key must be comparable, but got %T", key)
+	}
+
+	return nil
+}
+
+// mapKeyOf converts key to the key type of m, and returns false if impossible. Only conversions
+// preserving the value are accepted, e.g. int(1) to uint8(1), but neither 256 nor 1.5 to uint8.
+func mapKeyOf(m reflect.Value, key interface{}) (reflect.Value, bool) {
+	kval := reflect.ValueOf(key)
+	ktype := m.Type().Key()
+
+	if !kval.IsValid() {
+		switch ktype.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Chan:
+			return reflect.Zero(ktype), true
+		}
+
+		return kval, false
+	}
+
+	if !kval.Type().Comparable() {
+		return kval, false
+	}
+
+	if kval.Type().AssignableTo(ktype) {
+		return kval, true
+	}
+
+	if kval.Type().ConvertibleTo(ktype) && kval.Kind() != reflect.String && ktype.Kind() != reflect.String {
+		converted := kval.Convert(ktype)
+		if !converted.Type().ConvertibleTo(kval.Type()) || converted.Convert(kval.Type()).Interface() != kval.Interface() {
+			return kval, false
+		}
+
+		return converted, true
+	}
+
+	return kval, false
+}
+
+// lookupKey returns the value stored under key in m.
+func lookupKey(m reflect.Value, key interface{}) (reflect.Value, bool) {
+	kval, ok := mapKeyOf(m, key)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	value := m.MapIndex(kval)
+	return value, value.IsValid()
+}
+
+// closestKeys returns at most maxClosestKeys keys of m closest to key by edit distance of their formats.
+func (c *Config) closestKeys(m reflect.Value, key interface{}) string {
+	target := fmt.Sprintf("%v", key)
+
+	type candidate struct {
+		key      string
+		distance int
+	}
+
+	candidates := make([]candidate, 0, m.Len())
+	for _, k := range m.MapKeys() {
+		ks, _ := c.toString(k.Interface(), k.Interface())
+
+		candidates = append(candidates, candidate{
+			key:      ks,
+			distance: levenshtein(target, fmt.Sprintf("%v", k.Interface())),
+		})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+
+		return candidates[i].key < candidates[j].key
+	})
+
+	if len(candidates) > maxClosestKeys {
+		candidates = candidates[:maxClosestKeys]
+	}
+
+	keys := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		keys[i] = candidate.key
+	}
+
+	return strings.Join(keys, "\n")
+}
+
+// levenshtein returns the edit distance between a and b in runes.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return utf8.RuneCountInString(b)
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+
+	return a
+}
+
+// HasKey asserts that the specified map has the key.
+//
+//	assert.HasKey(t, map[string]int{"hello": 1}, "hello")
+//
+// Returns whether the assertion was successful (true) or not (false).
+func HasKey(t TestingT, m, key interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	mval, ok := assertMap(t, m, extras...)
+	if !ok || !assertKey(t, key, extras...) {
+		return false
+	}
+
+	if _, ok := lookupKey(mval, key); !ok {
+		c := configOf(t)
+		exps, _ := c.toString(key, key)
+
+		labels := []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-key",
+				content: exps,
+			},
+		}
+		if mval.Len() > 0 {
+			labels = append(labels, labeledOutput{
+				label:   "+closest keys",
+				content: c.closestKeys(mval, key),
+			})
+		}
+
+		return Errorf(t, "Expect to have the key", labels, arguments{
+			"m":   m,
+			"key": key,
+		})
+	}
+
+	return true
+}
+
+// HasValue asserts that the specified map has the value under any key.
+//
+//	assert.HasValue(t, map[string]int{"hello": 1}, 1)
+//
+// Returns whether the assertion was successful (true) or not (false).
+func HasValue(t TestingT, m, value interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	mval, ok := assertMap(t, m, extras...)
+	if !ok {
+		return false
+	}
+
+	for _, key := range mval.MapKeys() {
+		if DeepEqual(value, mval.MapIndex(key).Interface()) {
+			return true
+		}
+	}
+
+	c := configOf(t)
+	exps, _ := c.toString(value, value)
+
+	labels := []labeledOutput{
+		{
+			label:   labelMessages,
+			content: formatExtras(extras...),
+		},
+		{
+			label:   "-value",
+			content: exps,
+		},
+	}
+	if mval.Len() > 0 {
+		labels = append(labels, labeledOutput{
+			label:   "+entries",
+			content: c.formatEntries(mval, sortedKeys(mval)),
+		})
+	}
+
+	return Errorf(t, "Expect to have the value", labels, arguments{
+		"m":     m,
+		"value": value,
+	})
+}
+
+// entryMismatch returns labels explaining why the entry of key and value is not in m, or nil if it is.
+func (c *Config) entryMismatch(m reflect.Value, key, value interface{}) []labeledOutput {
+	ks, _ := c.toString(key, key)
+
+	actual, ok := lookupKey(m, key)
+	if !ok {
+		labels := []labeledOutput{
+			{
+				label:   "-key",
+				content: ks,
+			},
+		}
+		if m.Len() > 0 {
+			labels = append(labels, labeledOutput{
+				label:   "+closest keys",
+				content: c.closestKeys(m, key),
+			})
+		}
+
+		return labels
+	}
+
+	if DeepEqual(value, actual.Interface()) {
+		return nil
+	}
+
+	exps, acts := c.toString(value, actual.Interface())
+
+	return []labeledOutput{
+		{
+			label:   "-key",
+			content: ks,
+		},
+		{
+			label:   "-expected",
+			content: exps,
+		},
+		{
+			label:   "+received",
+			content: acts,
+		},
+		{
+			label:   labelDiff,
+			content: c.diff(value, actual.Interface()),
+		},
+	}
+}
+
+// HasEntry asserts that the specified map has the value under the key.
+//
+//	assert.HasEntry(t, map[string]int{"hello": 1}, "hello", 1)
+//
+// Returns whether the assertion was successful (true) or not (false).
+func HasEntry(t TestingT, m, key, value interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	mval, ok := assertMap(t, m, extras...)
+	if !ok || !assertKey(t, key, extras...) {
+		return false
+	}
+
+	if labels := configOf(t).entryMismatch(mval, key, value); labels != nil {
+		return Errorf(t, "Expect to have the entry", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+		}, labels, arguments{
+			"m":     m,
+			"key":   key,
+			"value": value,
+		})
+	}
+
+	return true
+}
+
+// MapSubset asserts that the specified map has all entries of expected, which is a map too.
+// Entries of the map not in expected are ignored.
+//
+//	assert.MapSubset(t, map[string]int{"a": 1, "b": 2}, map[string]int{"a": 1})
+//
+// Returns whether the assertion was successful (true) or not (false).
+func MapSubset(t TestingT, m, expected interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	mval, ok := assertMap(t, m, extras...)
+	if !ok {
+		return false
+	}
+
+	eval, ok := assertMap(t, expected, extras...)
+	if !ok {
+		return false
+	}
+
+	c := configOf(t)
+
+	var (
+		missing []string
+		changed []string
+	)
+	for _, key := range sortedKeys(eval) {
+		value := eval.MapIndex(key).Interface()
+		ks, _ := c.toString(key.Interface(), key.Interface())
+
+		actual, ok := lookupKey(mval, key.Interface())
+		if !ok {
+			if mval.Len() > 0 {
+				ks = fmt.Sprintf("%s (closest: %s)", ks, strings.Replace(c.closestKeys(mval, key.Interface()), "\n", ", ", -1))
+			}
+
+			missing = append(missing, ks)
+			continue
+		}
+
+		if !DeepEqual(value, actual.Interface()) {
+			exps, acts := c.toString(value, actual.Interface())
+
+			changed = append(changed, fmt.Sprintf("%s: expected %s, received %s", ks, exps, acts))
+		}
+	}
+
+	if len(missing) > 0 || len(changed) > 0 {
+		labels := []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+		}
+		if len(missing) > 0 {
+			labels = append(labels, labeledOutput{
+				label:   "-missing keys",
+				content: strings.Join(missing, "\n"),
+			})
+		}
+		if len(changed) > 0 {
+			labels = append(labels, labeledOutput{
+				label:   "+different values",
+				content: strings.Join(changed, "\n"),
+			})
+		}
+
+		return Errorf(t, fmt.Sprintf("Expect to have all entries, but %d key(s) missing and %d value(s) differ", len(missing), len(changed)), labels, arguments{
+			"m":        m,
+			"expected": expected,
+		})
+	}
+
+	return true
+}
+
+// KeysEqual asserts that keys of the specified map are exactly keys, which is a slice or an array
+// of keys, or another map whose values are ignored.
+//
+//	assert.KeysEqual(t, map[string]int{"a": 1, "b": 2}, []string{"b", "a"})
+//
+// Returns whether the assertion was successful (true) or not (false).
+func KeysEqual(t TestingT, m, keys interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	mval, ok := assertMap(t, m, extras...)
+	if !ok {
+		return false
+	}
+
+	var expected []interface{}
+	if kval := reflect.ValueOf(keys); kval.Kind() == reflect.Map {
+		for _, key := range sortedKeys(kval) {
+			expected = append(expected, key.Interface())
+		}
+	} else {
+		lists, ok := assertElements(t, extras, arguments{
+			"m":    m,
+			"keys": keys,
+		}, keys)
+		if !ok {
+			return false
+		}
+
+		expected = lists[0]
+	}
+
+	actual := make([]interface{}, 0, mval.Len())
+	for _, key := range sortedKeys(mval) {
+		actual = append(actual, key.Interface())
+	}
+
+	// keys of different types are converted to the key type of the map
+	for i, key := range expected {
+		if kval, ok := mapKeyOf(mval, key); ok && kval.IsValid() {
+			expected[i] = kval.Interface()
+		}
+	}
+
+	missing, extra := matchElements(expected, actual)
+	if len(missing) > 0 || len(extra) > 0 {
+		c := configOf(t)
+
+		labels := []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+		}
+		if len(missing) > 0 {
+			labels = append(labels, labeledOutput{
+				label:   "-missing keys",
+				content: c.formatKeys(expected, missing),
+			})
+		}
+		if len(extra) > 0 {
+			labels = append(labels, labeledOutput{
+				label:   "+extra keys",
+				content: c.formatKeys(actual, extra),
+			})
+		}
+
+		return Errorf(t, "Expect to have exactly the keys", labels, arguments{
+			"m":    m,
+			"keys": keys,
+		})
+	}
+
+	return true
+}
+
+// formatKeys returns keys at indexes one per line.
+func (c *Config) formatKeys(keys []interface{}, indexes []int) string {
+	lines := make([]string, len(indexes))
+	for i, index := range indexes {
+		lines[i], _ = c.toString(keys[index], keys[index])
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,149 @@
+package gospec
+
+import (
+	"testing"
+)
+
+func TestHasKey(t *testing.T) {
+	mockT := new(testing.T)
+
+	m := map[string]int{"hello": 1, "world": 2}
+
+	True(t, HasKey(mockT, m, "hello"))
+	True(t, HasKey(mockT, map[int64]bool{1: true}, 1))
+	True(t, HasKey(mockT, map[interface{}]int{nil: 1}, nil))
+	False(t, HasKey(mockT, m, "earth"))
+	False(t, HasKey(mockT, m, 1))
+	False(t, HasKey(mockT, map[string]int{"a": 1}, 97))
+	False(t, HasKey(mockT, []string{"hello"}, "hello"))
+
+	// only lossless conversions of keys are accepted
+	True(t, HasKey(mockT, map[uint8]int{255: 1}, 255))
+	True(t, HasKey(mockT, map[float64]int{1: 1}, 1))
+	False(t, HasKey(mockT, map[uint8]int{0: 1}, 256))
+	False(t, HasKey(mockT, map[int]int{1: 1}, 1.5))
+	False(t, HasKey(mockT, map[uint]int{1: 1}, -1))
+
+	// unhashable keys are invalid parameters
+	rt := new(RecordingT)
+	False(t, HasKey(rt, map[interface{}]int{1: 1}, []int{1}))
+	False(t, HasEntry(rt, map[interface{}]int{1: 1}, []int{1}, 1))
+	if Len(t, rt.Records(), 2) {
+		Equal(t, "Key must be comparable, but got []int", rt.Records()[0].Error)
+		Equal(t, "Key must be comparable, but got []int", rt.Records()[1].Error)
+	}
+
+	True(t, HasValue(mockT, m, 2))
+	False(t, HasValue(mockT, m, 3))
+	False(t, HasValue(mockT, m, "hello"))
+}
+
+func TestHasEntry(t *testing.T) {
+	mockT := new(testing.T)
+
+	m := map[string][]int{"hello": {1, 2}}
+
+	True(t, HasEntry(mockT, m, "hello", []int{1, 2}))
+	False(t, HasEntry(mockT, m, "hello", []int{1, 3}))
+	False(t, HasEntry(mockT, m, "helo", []int{1, 2}))
+
+	rt := new(RecordingT)
+
+	False(t, HasEntry(rt, map[string]int{"hello": 1, "help": 2, "world": 3, "word": 4}, "helo", 1))
+	False(t, HasEntry(rt, map[string]int{"hello": 1}, "hello", 2))
+
+	records := rt.Records()
+	if Len(t, records, 2) {
+		Equal(t, "\"hello\"\n\"help\"\n\"word\"", testingLabelOf(records[0], "+closest keys"))
+		Equal(t, "2", records[1].Expected)
+		Equal(t, "1", records[1].Actual)
+	}
+
+	// closest keys of empty maps are omitted
+	rt.Reset()
+
+	False(t, HasKey(rt, map[string]int{}, "hello"))
+	False(t, HasEntry(rt, map[string]int{}, "hello", 1))
+	False(t, MapSubset(rt, map[string]int{}, map[string]int{"hello": 1}))
+	False(t, HasValue(rt, map[string]int{}, 1))
+
+	records = rt.Records()
+	if Len(t, records, 4) {
+		for _, record := range records[:2] {
+			Equal(t, "-key", record.Labels[0].Label)
+			for _, label := range record.Labels {
+				NotEqual(t, "+closest keys", label.Label)
+			}
+		}
+
+		Equal(t, `"hello"`, testingLabelOf(records[2], "-missing keys"))
+		Empty(t, testingLabelOf(records[3], "+entries"))
+	}
+}
+
+func TestMapSubset(t *testing.T) {
+	mockT := new(testing.T)
+
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+
+	True(t, MapSubset(mockT, m, map[string]int{}))
+	True(t, MapSubset(mockT, m, map[string]int{"a": 1, "c": 3}))
+	False(t, MapSubset(mockT, m, map[string]int{"a": 2}))
+	False(t, MapSubset(mockT, m, map[string]int{"d": 1}))
+	False(t, MapSubset(mockT, m, []int{1}))
+
+	rt := new(RecordingT)
+
+	False(t, MapSubset(rt, m, map[string]int{"a": 2, "bb": 2}))
+
+	records := rt.Records()
+	if Len(t, records, 1) {
+		Contains(t, records[0].Error, "but 1 key(s) missing and 1 value(s) differ")
+		Contains(t, rt.String(), "\"bb\" (closest: \"b\", \"a\", \"c\")")
+		Contains(t, rt.String(), "\"a\": expected 2, received 1")
+	}
+
+	// empty labels are omitted
+	rt.Reset()
+
+	False(t, MapSubset(rt, m, map[string]int{"a": 2}))
+	if Len(t, rt.Records(), 1) {
+		for _, label := range rt.Records()[0].Labels {
+			NotEqual(t, "-missing keys", label.Label)
+		}
+		Equal(t, `"a": expected 2, received 1`, testingLabelOf(rt.Records()[0], "+different values"))
+	}
+}
+
+func TestKeysEqual(t *testing.T) {
+	mockT := new(testing.T)
+
+	m := map[string]int{"a": 1, "b": 2}
+
+	True(t, KeysEqual(mockT, m, []string{"b", "a"}))
+	True(t, KeysEqual(mockT, m, map[string]bool{"a": true, "b": false}))
+	True(t, KeysEqual(mockT, map[int64]int{1: 1}, []int{1}))
+	False(t, KeysEqual(mockT, m, []string{"a"}))
+	False(t, KeysEqual(mockT, m, []string{"a", "b", "c"}))
+	False(t, KeysEqual(mockT, m, "ab"))
+
+	// empty labels are omitted
+	rt := new(RecordingT)
+
+	False(t, KeysEqual(rt, m, []string{"a"}))
+	if Len(t, rt.Records(), 1) {
+		for _, label := range rt.Records()[0].Labels {
+			NotEqual(t, "-missing keys", label.Label)
+		}
+		Equal(t, `"b"`, testingLabelOf(rt.Records()[0], "+extra keys"))
+	}
+}
+
+func Test_levenshtein(t *testing.T) {
+	Equal(t, 0, levenshtein("", ""))
+	Equal(t, 3, levenshtein("", "abc"))
+	Equal(t, 3, levenshtein("abc", ""))
+	Equal(t, 1, levenshtein("helo", "hello"))
+	Equal(t, 3, levenshtein("kitten", "sitting"))
+	Equal(t, 1, levenshtein("héllo", "hello"))
+}
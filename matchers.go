@@ -0,0 +1,712 @@
+package gospec
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+type (
+	// Matcher is the interface of composable assertions used by Expect.
+	//
+	// Match reports whether actual is matched, and returns an error if actual can not be matched at all,
+	// e.g. it's of a wrong type. Describe and DescribeNegation return phrases completing "Expect to ...",
+	// such as "equal 1" and "not equal 1".
+	Matcher interface {
+		Match(actual interface{}) (bool, error)
+		Describe() string
+		DescribeNegation() string
+	}
+
+	// MismatchDescriber is an optional interface of Matcher explaining why actual is not matched,
+	// which is reported under the Mismatch label on failure.
+	MismatchDescriber interface {
+		DescribeMismatch(actual interface{}) string
+	}
+)
+
+// matcherOf returns v if it's a Matcher, or a matcher of values equal to v.
+func matcherOf(v interface{}) Matcher {
+	if m, ok := v.(Matcher); ok {
+		return m
+	}
+
+	return BeEqual(v)
+}
+
+// configurable is implemented by matchers formatting values, which are bound to the config of
+// the TestingT of Expect by withConfig.
+type configurable interface {
+	withConfig(c *Config) Matcher
+}
+
+// configure returns m bound to c if it's configurable.
+func configure(m Matcher, c *Config) Matcher {
+	if cm, ok := m.(configurable); ok {
+		return cm.withConfig(c)
+	}
+
+	return m
+}
+
+// configureAll returns matchers bound to c.
+func configureAll(matchers []Matcher, c *Config) []Matcher {
+	configured := make([]Matcher, len(matchers))
+	for i, m := range matchers {
+		configured[i] = configure(m, c)
+	}
+
+	return configured
+}
+
+// matcherConfig is the config of matchers, which is the global config unless they're bound to another.
+type matcherConfig struct {
+	c *Config
+}
+
+func (mc matcherConfig) config() *Config {
+	if mc.c != nil {
+		return mc.c
+	}
+
+	c := GetConfig()
+	return &c
+}
+
+// describeMismatch returns the mismatch of m if it implements MismatchDescriber.
+func describeMismatch(m Matcher, actual interface{}) string {
+	if describer, ok := m.(MismatchDescriber); ok {
+		return describer.DescribeMismatch(actual)
+	}
+
+	return ""
+}
+
+// Expectation binds an actual value to matchers, see Expect.
+type Expectation struct {
+	t      TestingT
+	actual interface{}
+}
+
+// Expect returns an expectation of actual for asserting with matchers.
+//
+//	assert.Expect(t, users).To(assert.HaveEach(
+//		assert.HaveField("Name", assert.MatchRegexp("^usr_")),
+//	))
+func Expect(t TestingT, actual interface{}) *Expectation {
+	return &Expectation{
+		t:      t,
+		actual: actual,
+	}
+}
+
+// To asserts that the actual value matches m, which is compared with Equal semantic if it's not a Matcher.
+//
+// Returns whether the assertion was successful (true) or not (false).
+func (e *Expectation) To(m interface{}, extras ...interface{}) bool {
+	if h := helperOf(e.t); h != nil {
+		h.Helper()
+	}
+
+	return e.expect(matcherOf(m), true, extras...)
+}
+
+// NotTo asserts that the actual value does not match m, which is compared with Equal semantic
+// if it's not a Matcher.
+//
+// Returns whether the assertion was successful (true) or not (false).
+func (e *Expectation) NotTo(m interface{}, extras ...interface{}) bool {
+	if h := helperOf(e.t); h != nil {
+		h.Helper()
+	}
+
+	return e.expect(matcherOf(m), false, extras...)
+}
+
+func (e *Expectation) expect(m Matcher, expected bool, extras ...interface{}) bool {
+	if h := helperOf(e.t); h != nil {
+		h.Helper()
+	}
+
+	c := configOf(e.t)
+	m = configure(m, c)
+
+	_, acts := c.toString(e.actual, e.actual)
+
+	ok, err := m.Match(e.actual)
+	if err != nil {
+		return Errorf(e.t, "Matcher failed: "+err.Error(), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "+received",
+				content: acts,
+			},
+		})
+	}
+
+	if ok == expected {
+		return true
+	}
+
+	description := m.Describe()
+	mismatch := ""
+	if expected {
+		mismatch = describeMismatch(m, e.actual)
+	} else {
+		description = m.DescribeNegation()
+	}
+
+	labels := []labeledOutput{
+		{
+			label:   labelMessages,
+			content: formatExtras(extras...),
+		},
+		{
+			label:   "+received",
+			content: acts,
+		},
+	}
+	if mismatch != "" {
+		// mismatches of equal matchers are unified diffs, which are rendered as Equal does
+		label := "Mismatch"
+		if _, ok := m.(equalMatcher); ok {
+			label = labelDiff
+		}
+
+		labels = append(labels, labeledOutput{
+			label:   label,
+			content: mismatch,
+		})
+	}
+
+	return Errorf(e.t, "Expect to "+description, labels)
+}
+
+type equalMatcher struct {
+	matcherConfig
+
+	expected interface{}
+}
+
+// BeEqual returns a matcher of values equal to expected, which is the same semantic of Equal.
+func BeEqual(expected interface{}) Matcher {
+	return equalMatcher{
+		expected: expected,
+	}
+}
+
+func (m equalMatcher) Match(actual interface{}) (bool, error) {
+	return DeepEqual(m.expected, actual), nil
+}
+
+func (m equalMatcher) Describe() string {
+	exps, _ := m.config().toString(m.expected, m.expected)
+
+	return "equal " + exps
+}
+
+func (m equalMatcher) DescribeNegation() string {
+	return "not " + m.Describe()
+}
+
+func (m equalMatcher) DescribeMismatch(actual interface{}) string {
+	return m.config().diff(m.expected, actual)
+}
+
+func (m equalMatcher) withConfig(c *Config) Matcher {
+	m.c = c
+	return m
+}
+
+type nilMatcher struct{}
+
+// BeNil returns a matcher of nil values, which is the same semantic of Nil.
+func BeNil() Matcher {
+	return nilMatcher{}
+}
+
+func (nilMatcher) Match(actual interface{}) (bool, error) {
+	return IsNil(actual), nil
+}
+
+func (nilMatcher) Describe() string {
+	return "be nil"
+}
+
+func (nilMatcher) DescribeNegation() string {
+	return "not be nil"
+}
+
+type regexpMatcher struct {
+	pattern string
+}
+
+// MatchRegexp returns a matcher of strings, byte slices and fmt.Stringer values matching pattern.
+func MatchRegexp(pattern string) Matcher {
+	return regexpMatcher{pattern: pattern}
+}
+
+func (m regexpMatcher) Match(actual interface{}) (bool, error) {
+	reg, err := regexp.Compile(m.pattern)
+	if err != nil {
+		return false, err
+	}
+
+	switch v := actual.(type) {
+	case string:
+		return reg.MatchString(v), nil
+
+	case []byte:
+		return reg.Match(v), nil
+
+	case fmt.Stringer:
+		return reg.MatchString(v.String()), nil
+
+	}
+
+	return false, fmt.Errorf("expect a string, []byte or fmt.Stringer to match regexp, but got %T", actual)
+}
+
+func (m regexpMatcher) Describe() string {
+	return fmt.Sprintf("match regexp %q", m.pattern)
+}
+
+func (m regexpMatcher) DescribeNegation() string {
+	return fmt.Sprintf("not match regexp %q", m.pattern)
+}
+
+type lenMatcher struct {
+	length int
+}
+
+// HaveLen returns a matcher of values having length, which is the same semantic of Len.
+func HaveLen(length int) Matcher {
+	return lenMatcher{length: length}
+}
+
+func (m lenMatcher) Match(actual interface{}) (bool, error) {
+	n, ok := tryLen(actual)
+	if !ok {
+		return false, fmt.Errorf("expect to apply buildin len() on %T", actual)
+	}
+
+	return n == m.length, nil
+}
+
+func (m lenMatcher) Describe() string {
+	return fmt.Sprintf("have %d item(s)", m.length)
+}
+
+func (m lenMatcher) DescribeNegation() string {
+	return fmt.Sprintf("not have %d item(s)", m.length)
+}
+
+func (m lenMatcher) DescribeMismatch(actual interface{}) string {
+	n, _ := tryLen(actual)
+
+	return fmt.Sprintf("has %d item(s)", n)
+}
+
+// describeMatchers joins descriptions of matchers with the conjunction.
+func describeMatchers(matchers []Matcher, conjunction string) string {
+	descriptions := make([]string, len(matchers))
+	for i, m := range matchers {
+		descriptions[i] = m.Describe()
+	}
+
+	return "(" + strings.Join(descriptions, ") "+conjunction+" (") + ")"
+}
+
+type allOfMatcher struct {
+	matchers []Matcher
+}
+
+// AllOf returns a matcher of values matching all of matchers, which are compared with Equal semantic
+// if they're not Matchers. It's short-circuited on the first unmatched one.
+func AllOf(matchers ...interface{}) Matcher {
+	m := allOfMatcher{matchers: make([]Matcher, len(matchers))}
+	for i, matcher := range matchers {
+		m.matchers[i] = matcherOf(matcher)
+	}
+
+	return m
+}
+
+func (m allOfMatcher) Match(actual interface{}) (bool, error) {
+	for _, matcher := range m.matchers {
+		ok, err := matcher.Match(actual)
+		if !ok || err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func (m allOfMatcher) Describe() string {
+	return describeMatchers(m.matchers, "and")
+}
+
+func (m allOfMatcher) DescribeNegation() string {
+	return "not all of " + m.Describe()
+}
+
+func (m allOfMatcher) withConfig(c *Config) Matcher {
+	m.matchers = configureAll(m.matchers, c)
+	return m
+}
+
+func (m allOfMatcher) DescribeMismatch(actual interface{}) string {
+	for _, matcher := range m.matchers {
+		if ok, _ := matcher.Match(actual); !ok {
+			mismatch := "failed to " + matcher.Describe()
+			if detail := describeMismatch(matcher, actual); detail != "" {
+				mismatch += ": " + detail
+			}
+
+			return mismatch
+		}
+	}
+
+	return ""
+}
+
+type anyOfMatcher struct {
+	matchers []Matcher
+}
+
+// AnyOf returns a matcher of values matching any of matchers, which are compared with Equal semantic
+// if they're not Matchers. Errors of matchers are ignored unless none of them matches.
+func AnyOf(matchers ...interface{}) Matcher {
+	m := anyOfMatcher{matchers: make([]Matcher, len(matchers))}
+	for i, matcher := range matchers {
+		m.matchers[i] = matcherOf(matcher)
+	}
+
+	return m
+}
+
+func (m anyOfMatcher) Match(actual interface{}) (bool, error) {
+	var errs []string
+	for _, matcher := range m.matchers {
+		ok, err := matcher.Match(actual)
+		if ok && err == nil {
+			return true, nil
+		}
+
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) == len(m.matchers) && len(errs) > 0 {
+		return false, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+
+	return false, nil
+}
+
+func (m anyOfMatcher) Describe() string {
+	return describeMatchers(m.matchers, "or")
+}
+
+func (m anyOfMatcher) DescribeNegation() string {
+	return "not any of " + m.Describe()
+}
+
+func (m anyOfMatcher) withConfig(c *Config) Matcher {
+	m.matchers = configureAll(m.matchers, c)
+	return m
+}
+
+type notMatcher struct {
+	matcher Matcher
+}
+
+// Not returns a matcher of values not matching m, which is compared with Equal semantic if it's not a Matcher.
+func Not(m interface{}) Matcher {
+	return notMatcher{matcher: matcherOf(m)}
+}
+
+func (m notMatcher) Match(actual interface{}) (bool, error) {
+	ok, err := m.matcher.Match(actual)
+
+	return !ok && err == nil, err
+}
+
+func (m notMatcher) Describe() string {
+	return m.matcher.DescribeNegation()
+}
+
+func (m notMatcher) DescribeNegation() string {
+	return m.matcher.Describe()
+}
+
+func (m notMatcher) withConfig(c *Config) Matcher {
+	m.matcher = configure(m.matcher, c)
+	return m
+}
+
+type fieldMatcher struct {
+	matcherConfig
+
+	name    string
+	matcher Matcher
+}
+
+// HaveField returns a matcher of structs, or pointers to structs, whose field of name matches m,
+// which is compared with Equal semantic if it's not a Matcher. Nested fields are separated by dots,
+// such as "Profile.Age".
+func HaveField(name string, m interface{}) Matcher {
+	return fieldMatcher{
+		name:    name,
+		matcher: matcherOf(m),
+	}
+}
+
+// field returns the field of actual by name.
+func (m fieldMatcher) field(actual interface{}) (interface{}, error) {
+	rval := reflect.ValueOf(actual)
+
+	for _, name := range strings.Split(m.name, ".") {
+		for rval.Kind() == reflect.Ptr || rval.Kind() == reflect.Interface {
+			if rval.IsNil() {
+				return nil, fmt.Errorf("expect a struct to have field %s, but got nil", m.name)
+			}
+
+			rval = rval.Elem()
+		}
+
+		if rval.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("expect a struct to have field %s, but got %T", m.name, actual)
+		}
+
+		field, ok := rval.Type().FieldByName(name)
+		if !ok {
+			return nil, fmt.Errorf("expect %s to have field %s", rval.Type(), m.name)
+		}
+		if field.PkgPath != "" {
+			return nil, fmt.Errorf("expect field %s of %s to be exported", name, rval.Type())
+		}
+
+		// promoted fields may sit behind nil embedded pointers
+		for i, index := range field.Index {
+			if i > 0 && rval.Kind() == reflect.Ptr {
+				if rval.IsNil() {
+					return nil, fmt.Errorf("expect a struct to have field %s, but got nil embedded struct %s", m.name, rval.Type())
+				}
+
+				rval = rval.Elem()
+			}
+
+			rval = rval.Field(index)
+		}
+	}
+
+	return rval.Interface(), nil
+}
+
+func (m fieldMatcher) Match(actual interface{}) (bool, error) {
+	value, err := m.field(actual)
+	if err != nil {
+		return false, err
+	}
+
+	return m.matcher.Match(value)
+}
+
+func (m fieldMatcher) Describe() string {
+	return fmt.Sprintf("have field %s which should %s", m.name, m.matcher.Describe())
+}
+
+func (m fieldMatcher) DescribeNegation() string {
+	return fmt.Sprintf("have field %s which should %s", m.name, m.matcher.DescribeNegation())
+}
+
+func (m fieldMatcher) DescribeMismatch(actual interface{}) string {
+	value, err := m.field(actual)
+	if err != nil {
+		return err.Error()
+	}
+
+	_, acts := m.config().toString(value, value)
+
+	mismatch := fmt.Sprintf("field %s is %s", m.name, acts)
+	if detail := describeMismatch(m.matcher, value); detail != "" {
+		mismatch += ": " + detail
+	}
+
+	return mismatch
+}
+
+func (m fieldMatcher) withConfig(c *Config) Matcher {
+	m.c = c
+	m.matcher = configure(m.matcher, c)
+	return m
+}
+
+type keyValueMatcher struct {
+	matcherConfig
+
+	key     interface{}
+	matcher Matcher
+}
+
+// HaveKeyWithValue returns a matcher of maps having the key whose value matches value,
+// which is compared with Equal semantic if it's not a Matcher.
+func HaveKeyWithValue(key, value interface{}) Matcher {
+	return keyValueMatcher{
+		key:     key,
+		matcher: matcherOf(value),
+	}
+}
+
+func (m keyValueMatcher) Match(actual interface{}) (bool, error) {
+	mval := reflect.ValueOf(actual)
+	if mval.Kind() != reflect.Map {
+		return false, fmt.Errorf("expect a map, but got %T", actual)
+	}
+	if err := checkKey(m.key); err != nil {
+		return false, err
+	}
+
+	value, ok := lookupKey(mval, m.key)
+	if !ok {
+		return false, nil
+	}
+
+	return m.matcher.Match(value.Interface())
+}
+
+func (m keyValueMatcher) Describe() string {
+	ks, _ := m.config().toString(m.key, m.key)
+
+	return fmt.Sprintf("have key %s whose value should %s", ks, m.matcher.Describe())
+}
+
+func (m keyValueMatcher) DescribeNegation() string {
+	ks, _ := m.config().toString(m.key, m.key)
+
+	return fmt.Sprintf("not have key %s whose value should %s", ks, m.matcher.Describe())
+}
+
+func (m keyValueMatcher) DescribeMismatch(actual interface{}) string {
+	mval := reflect.ValueOf(actual)
+	if mval.Kind() != reflect.Map {
+		return ""
+	}
+
+	value, ok := lookupKey(mval, m.key)
+	if !ok {
+		return "closest keys: " + strings.Replace(m.config().closestKeys(mval, m.key), "\n", ", ", -1)
+	}
+
+	_, acts := m.config().toString(value.Interface(), value.Interface())
+
+	mismatch := "value is " + acts
+	if detail := describeMismatch(m.matcher, value.Interface()); detail != "" {
+		mismatch += ": " + detail
+	}
+
+	return mismatch
+}
+
+func (m keyValueMatcher) withConfig(c *Config) Matcher {
+	m.c = c
+	m.matcher = configure(m.matcher, c)
+	return m
+}
+
+type eachMatcher struct {
+	matcherConfig
+
+	matcher Matcher
+
+	// received keeps elements received from channels by their pointers, which are bound by
+	// withConfig for each Expect, so mismatches are described with elements matched.
+	received map[uintptr][]entry
+}
+
+// HaveEach returns a matcher of slices, arrays, maps and channels whose every element matches m,
+// which is compared with Equal semantic if it's not a Matcher. Empty collections are matched.
+// NOTE: elements of receive-only channels are consumed, while others are sent back.
+func HaveEach(m interface{}) Matcher {
+	return eachMatcher{matcher: matcherOf(m)}
+}
+
+// entries returns elements of actual, those received from channels are kept for DescribeMismatch.
+func (m eachMatcher) entries(actual interface{}) ([]entry, bool) {
+	rval := reflect.ValueOf(actual)
+	if rval.Kind() != reflect.Chan || m.received == nil {
+		return entriesOf(m.config(), actual)
+	}
+
+	if entries, ok := m.received[rval.Pointer()]; ok {
+		return entries, true
+	}
+
+	entries, ok := entriesOf(m.config(), actual)
+	if ok {
+		m.received[rval.Pointer()] = entries
+	}
+
+	return entries, ok
+}
+
+func (m eachMatcher) Match(actual interface{}) (bool, error) {
+	entries, ok := m.entries(actual)
+	if !ok {
+		return false, fmt.Errorf("expect a slice, an array, a map or a channel, but got %T", actual)
+	}
+
+	for _, e := range entries {
+		ok, err := m.matcher.Match(e.value.Interface())
+		if !ok || err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func (m eachMatcher) Describe() string {
+	return "have each element which should " + m.matcher.Describe()
+}
+
+func (m eachMatcher) DescribeNegation() string {
+	return "have any element which should " + m.matcher.DescribeNegation()
+}
+
+func (m eachMatcher) DescribeMismatch(actual interface{}) string {
+	c := m.config()
+
+	entries, _ := m.entries(actual)
+
+	var lines []string
+	for _, e := range entries {
+		value := e.value.Interface()
+		if ok, _ := m.matcher.Match(value); ok {
+			continue
+		}
+
+		line := e.name + " " + c.valueString(e.value)
+		if detail := describeMismatch(m.matcher, value); detail != "" {
+			line += ": " + detail
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m eachMatcher) withConfig(c *Config) Matcher {
+	m.c = c
+	m.matcher = configure(m.matcher, c)
+	m.received = make(map[uintptr][]entry)
+	return m
+}
@@ -0,0 +1,176 @@
+package gospec
+
+import (
+	"testing"
+)
+
+type testingProfile struct {
+	Age int
+}
+
+type testingUser struct {
+	Name    string
+	Profile *testingProfile
+	secret  string
+}
+
+func TestExpect(t *testing.T) {
+	mockT := new(testing.T)
+
+	users := []testingUser{
+		{Name: "usr_alice", Profile: &testingProfile{Age: 17}},
+		{Name: "usr_bob", Profile: &testingProfile{Age: 42}},
+	}
+
+	trueCases := []struct {
+		actual  interface{}
+		matcher interface{}
+	}{
+		{1, 1},
+		{1, BeEqual(1)},
+		{nil, BeNil()},
+		{"usr_alice", MatchRegexp("^usr_")},
+		{[]byte("usr_alice"), MatchRegexp("^usr_")},
+		{users, HaveLen(2)},
+		{users, HaveEach(HaveField("Name", MatchRegexp("^usr_")))},
+		{users[0], HaveField("Profile.Age", 17)},
+		{&users[0], HaveField("Name", Not("usr_bob"))},
+		{map[string]int{"a": 1}, HaveKeyWithValue("a", 1)},
+		{map[string]int{"a": 1}, HaveKeyWithValue("a", AnyOf(0, 1))},
+		{"usr_alice", AllOf(MatchRegexp("^usr_"), HaveLen(9))},
+		{[]int{}, HaveEach(1)},
+		{1, AnyOf(MatchRegexp("^1"), 1)},
+	}
+
+	for i, tc := range trueCases {
+		True(t, Expect(mockT, tc.actual).To(tc.matcher), "Expect should return true for trueCases(%d)", i)
+	}
+
+	falseCases := []struct {
+		actual  interface{}
+		matcher interface{}
+	}{
+		{1, 2},
+		{1, BeNil()},
+		{1, MatchRegexp("^1")},
+		{"a", MatchRegexp("(")},
+		{users, HaveLen(3)},
+		{1, HaveLen(1)},
+		{users, HaveEach(HaveField("Profile.Age", 17))},
+		{users[0], HaveField("Missing", 17)},
+		{users[0], HaveField("secret", "")},
+		{testingUser{}, HaveField("Profile.Age", 0)},
+		{map[string]int{"a": 1}, HaveKeyWithValue("b", 1)},
+		{map[string]int{"a": 1}, HaveKeyWithValue("a", 2)},
+		{[]int{1}, HaveKeyWithValue(0, 1)},
+		{map[interface{}]int{1: 1}, HaveKeyWithValue([]int{1}, 1)},
+		{"usr_alice", AllOf(MatchRegexp("^usr_"), HaveLen(3))},
+		{1, AnyOf(2, 3)},
+		{1, Not(1)},
+		{1, HaveEach(1)},
+	}
+
+	for i, fc := range falseCases {
+		False(t, Expect(mockT, fc.actual).To(fc.matcher), "Expect should return false for falseCases(%d)", i)
+	}
+
+	True(t, Expect(mockT, 1).NotTo(2))
+	True(t, Expect(mockT, users).NotTo(HaveEach(HaveField("Profile.Age", 17))))
+	False(t, Expect(mockT, 1).NotTo(AnyOf(1, 2)))
+	False(t, Expect(mockT, 1).NotTo(MatchRegexp("1")))
+}
+
+func TestExpectWithReporter(t *testing.T) {
+	rt := new(RecordingT)
+
+	users := []testingUser{
+		{Name: "usr_alice"},
+		{Name: "bob"},
+	}
+
+	False(t, Expect(rt, users).To(HaveEach(HaveField("Name", MatchRegexp("^usr_")))))
+
+	records := rt.Records()
+	if Len(t, records, 1) {
+		Equal(t, `Expect to have each element which should have field Name which should match regexp "^usr_"`, records[0].Error)
+		Contains(t, testingLabelOf(records[0], "Mismatch"), `[1] gospec.testingUser{Name:"bob"`)
+		Contains(t, testingLabelOf(records[0], "Mismatch"), `field Name is "bob"`)
+	}
+}
+
+func TestExpectWithConfig(t *testing.T) {
+	rt := new(RecordingT)
+
+	assert := WithConfig(rt, func(c *Config) {
+		c.Stringers = true
+	})
+
+	id := testingID{0xde, 0xad, 0xbe, 0xef}
+
+	False(t, Expect(assert, id).To(AllOf(Not(BeNil()), BeEqual(testingID{}))))
+	False(t, Expect(assert, map[string]testingID{"a": id}).To(HaveKeyWithValue("a", testingID{})))
+	False(t, Expect(rt, id).To(BeEqual(testingID{})))
+
+	records := rt.Records()
+	if Len(t, records, 3) {
+		Equal(t, "Expect to (not be nil) and (equal 0000-0000)", records[0].Error)
+		Equal(t, `Expect to have key "a" whose value should equal 0000-0000`, records[1].Error)
+		Equal(t, "Expect to equal gospec.testingID{0x0, 0x0, 0x0, 0x0}", records[2].Error)
+
+		// diffs of equal matchers are reported as Equal does
+		Equal(t, "", testingLabelOf(records[2], "Mismatch"))
+		Equal(t, records[2].Diff, testingLabelOf(records[2], labelDiff))
+		Contains(t, records[2].Diff, "--- Expected\n+++ Actual\n")
+	}
+
+	// empty mismatches are omitted
+	rt.Reset()
+
+	False(t, Expect(rt, 1).To(BeNil()))
+	if Len(t, rt.Records(), 1) {
+		for _, label := range rt.Records()[0].Labels {
+			NotEqual(t, "Mismatch", label.Label)
+		}
+	}
+}
+
+func TestHaveFieldWithNilEmbeddedStruct(t *testing.T) {
+	type inner struct {
+		Name string
+	}
+	type Inner = inner
+	type outer struct {
+		*Inner
+	}
+
+	rt := new(RecordingT)
+
+	NotPanics(t, func() {
+		False(t, Expect(rt, outer{}).To(HaveField("Name", "x")))
+	})
+	True(t, Expect(rt, outer{Inner: &Inner{Name: "x"}}).To(HaveField("Name", "x")))
+
+	records := rt.Records()
+	if Len(t, records, 1) {
+		Equal(t, "Matcher failed: expect a struct to have field Name, but got nil embedded struct *gospec.inner", records[0].Error)
+	}
+}
+
+func TestHaveEachWithReceiveOnlyChannel(t *testing.T) {
+	rt := new(RecordingT)
+
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	ch <- 3
+
+	var recv <-chan int = ch
+
+	False(t, Expect(rt, recv).To(HaveEach(Not(2))))
+	Equal(t, 0, len(ch))
+
+	records := rt.Records()
+	if Len(t, records, 1) {
+		Equal(t, "[1] 2", testingLabelOf(records[0], "Mismatch"))
+	}
+}
@@ -0,0 +1,179 @@
+package gospec
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+)
+
+// numeralPrec is the precision of arithmetic of numerals, which is exact for all builtin numerics.
+const numeralPrec = 512
+
+var (
+	bigIntType   = reflect.TypeOf((*big.Int)(nil))
+	bigFloatType = reflect.TypeOf((*big.Float)(nil))
+	bigRatType   = reflect.TypeOf((*big.Rat)(nil))
+)
+
+// isBigType returns true if rtype is *big.Int, *big.Float or *big.Rat.
+func isBigType(rtype reflect.Type) bool {
+	return rtype == bigIntType || rtype == bigFloatType || rtype == bigRatType
+}
+
+// numeral is a numeric value of any kind in arbitrary precision, which avoids precision loss of
+// int64 and uint64 converted to float64.
+type numeral struct {
+	re, im   *big.Float
+	nan      bool
+	complex  bool
+	float32s bool
+}
+
+// numeralOf returns the numeral of v, which can be of any int, uint, float or complex kind
+// including named types, or a *big.Int, *big.Float or *big.Rat.
+func numeralOf(v interface{}) (numeral, bool) {
+	return numeralOfValue(reflect.ValueOf(v))
+}
+
+func numeralOfValue(rval reflect.Value) (n numeral, ok bool) {
+	if !rval.IsValid() {
+		return n, false
+	}
+
+	n.im = new(big.Float).SetPrec(numeralPrec)
+	n.re = new(big.Float).SetPrec(numeralPrec)
+
+	switch rval.Type() {
+	case bigIntType, bigFloatType, bigRatType:
+		if rval.IsNil() || !rval.CanInterface() {
+			return n, false
+		}
+
+		switch bv := rval.Interface().(type) {
+		case *big.Int:
+			n.re.SetInt(bv)
+
+		case *big.Float:
+			n.re.Set(bv)
+
+		case *big.Rat:
+			n.re.SetRat(bv)
+
+		}
+
+		return n, true
+	}
+
+	switch rval.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n.re.SetInt64(rval.Int())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n.re.SetUint64(rval.Uint())
+
+	case reflect.Float32, reflect.Float64:
+		n.float32s = rval.Kind() == reflect.Float32
+
+		if !n.setFloat(n.re, rval.Float()) {
+			return n, true
+		}
+
+	case reflect.Complex64, reflect.Complex128:
+		n.complex = true
+		n.float32s = rval.Kind() == reflect.Complex64
+
+		c := rval.Complex()
+		if !n.setFloat(n.re, real(c)) || !n.setFloat(n.im, imag(c)) {
+			return n, true
+		}
+
+	default:
+		return n, false
+
+	}
+
+	return n, true
+}
+
+// setFloat sets f to v, and marks the numeral as NaN if v is NaN.
+func (n *numeral) setFloat(f *big.Float, v float64) bool {
+	if math.IsNaN(v) {
+		n.nan = true
+		return false
+	}
+
+	f.SetFloat64(v)
+	return true
+}
+
+// isInf returns true if any part of the numeral is infinite.
+func (n numeral) isInf() bool {
+	return n.re.IsInf() || n.im.IsInf()
+}
+
+// equal returns true if two numerals are the same number.
+func (n numeral) equal(other numeral) bool {
+	return !n.nan && !other.nan && n.re.Cmp(other.re) == 0 && n.im.Cmp(other.im) == 0
+}
+
+// float64 returns the nearest float64 of the real part of the numeral.
+func (n numeral) float64() float64 {
+	if n.nan {
+		return math.NaN()
+	}
+
+	f, _ := n.re.Float64()
+	return f
+}
+
+// abs returns |n|, which is the modulus for complex numerals. It MUST NOT be infinite.
+func (n numeral) abs() *big.Float {
+	if !n.complex {
+		return new(big.Float).SetPrec(numeralPrec).Abs(n.re)
+	}
+
+	re := new(big.Float).SetPrec(numeralPrec).Mul(n.re, n.re)
+	im := new(big.Float).SetPrec(numeralPrec).Mul(n.im, n.im)
+
+	return re.Sqrt(re.Add(re, im))
+}
+
+// sub returns n - other exactly for finite numerals.
+func (n numeral) sub(other numeral) numeral {
+	return numeral{
+		re:      new(big.Float).SetPrec(numeralPrec).Sub(n.re, other.re),
+		im:      new(big.Float).SetPrec(numeralPrec).Sub(n.im, other.im),
+		complex: n.complex || other.complex,
+	}
+}
+
+// distance returns |n - other| exactly for finite numerals.
+func (n numeral) distance(other numeral) *big.Float {
+	return n.sub(other).abs()
+}
+
+// isZero returns true if the numeral is zero.
+func (n numeral) isZero() bool {
+	return !n.nan && n.re.Sign() == 0 && n.im.Sign() == 0
+}
+
+// String returns the numeral in decimal, integers are printed in full precision.
+func (n numeral) String() string {
+	switch {
+	case n.nan:
+		return "NaN"
+
+	case n.complex:
+		re, _ := n.re.Float64()
+		im, _ := n.im.Float64()
+
+		return fmt.Sprint(complex(re, im))
+
+	case n.re.IsInt() && !n.re.IsInf():
+		return n.re.Text('f', 0)
+
+	}
+
+	return fmt.Sprint(n.float64())
+}
@@ -0,0 +1,98 @@
+package gospec
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+type testingCelsius float64
+
+type testingPort uint16
+
+func Test_numeralOf(t *testing.T) {
+	trueCases := []struct {
+		v        interface{}
+		expected string
+	}{
+		{testingCelsius(36.5), "36.5"},
+		{testingPort(8080), "8080"},
+		{uint(1), "1"},
+		{uintptr(2), "2"},
+		{int64(math.MaxInt64), "9223372036854775807"},
+		{uint64(math.MaxUint64), "18446744073709551615"},
+		{complex(1, -2), "(1-2i)"},
+		{complex64(complex(0.5, 1)), "(0.5+1i)"},
+		{big.NewInt(42), "42"},
+		{new(big.Int).Lsh(big.NewInt(1), 100), "1267650600228229401496703205376"},
+		{big.NewFloat(1.5), "1.5"},
+		{big.NewRat(1, 4), "0.25"},
+		{math.NaN(), "NaN"},
+	}
+
+	for i, tc := range trueCases {
+		n, ok := numeralOf(tc.v)
+		True(t, ok, "numeralOf should return true for trueCases(%d)", i)
+		Equal(t, tc.expected, n.String(), "numeralOf should return %s for trueCases(%d)", tc.expected, i)
+	}
+
+	falseCases := []interface{}{
+		nil,
+		"1",
+		true,
+		(*big.Int)(nil),
+		[]int{1},
+	}
+
+	for i, fc := range falseCases {
+		_, ok := numeralOf(fc)
+		False(t, ok, "numeralOf should return false for falseCases(%d)", i)
+	}
+}
+
+func TestInDeltaWithNumerals(t *testing.T) {
+	mockT := new(testing.T)
+
+	trueCases := []struct {
+		a, b  interface{}
+		delta float64
+	}{
+		{testingCelsius(36.5), testingCelsius(36.6), 0.2},
+		{testingPort(80), testingPort(81), 1},
+		{uint(2), uint(1), 1},
+		{uintptr(2), uintptr(1), 1},
+		{complex(1, 1), complex(1, 1.5), 0.5},
+		{big.NewInt(100), big.NewInt(101), 1},
+		{big.NewFloat(0.5), 0.25, 0.25},
+		{big.NewRat(1, 3), big.NewRat(1, 3), 0},
+		{uint64(math.MaxUint64), uint64(math.MaxUint64 - 1), 1},
+	}
+
+	for i, tc := range trueCases {
+		True(t, InDelta(mockT, tc.a, tc.b, tc.delta), "InDelta should return true for trueCases(%d)", i)
+	}
+
+	falseCases := []struct {
+		a, b  interface{}
+		delta float64
+	}{
+		// float64(int64) loses precision near the limits, both are 9.223372036854776e+18
+		{int64(math.MaxInt64), int64(math.MaxInt64 - 2), 1},
+		{uint64(math.MaxUint64), uint64(math.MaxUint64 - 2), 1},
+		{complex(1, 1), complex(2, 2), 1},
+		{big.NewRat(1, 3), 0.3333, 1e-5},
+		{(*big.Int)(nil), 0, 1},
+		{1, 1, -1},
+		{1, 1, math.NaN()},
+	}
+
+	for i, fc := range falseCases {
+		False(t, InDelta(mockT, fc.a, fc.b, fc.delta), "InDelta should return false for falseCases(%d)", i)
+	}
+
+	True(t, InEpsilon(mockT, big.NewInt(1000000), big.NewInt(1000001), 2e-6))
+	True(t, InEpsilon(mockT, complex(3, 4), complex(3, 4.1), 0.1))
+	False(t, InULPs(mockT, complex(3, 4), complex(3, 4), 1))
+	True(t, Greater(mockT, big.NewInt(2), big.NewInt(1)))
+	True(t, Less(mockT, testingCelsius(-1), testingCelsius(0)))
+}
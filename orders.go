@@ -0,0 +1,453 @@
+package gospec
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// compareValues returns -1, 0 or +1 if a is less than, equal to or greater than b. It returns
+// false if a and b are not of the same ordered type, which are real numerics, strings, time.Time,
+// time.Duration, *big.Int, *big.Float and *big.Rat, including named types of them.
+func compareValues(a, b interface{}) (int, bool) {
+	return compareReflectValues(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+// compareReflectValues is compareValues of reflect values, and values of interfaces, e.g. elements
+// of []interface{}, are compared by their dynamic values.
+func compareReflectValues(aval, bval reflect.Value) (int, bool) {
+	if aval.Kind() == reflect.Interface {
+		aval = aval.Elem()
+	}
+	if bval.Kind() == reflect.Interface {
+		bval = bval.Elem()
+	}
+	if !aval.IsValid() || !bval.IsValid() || aval.Type() != bval.Type() {
+		return 0, false
+	}
+
+	if aval.Type() == timeType || aval.Type().ConvertibleTo(timeType) && aval.Kind() == reflect.Struct {
+		at := aval.Convert(timeType).Interface().(time.Time)
+		bt := bval.Convert(timeType).Interface().(time.Time)
+
+		switch {
+		case at.Before(bt):
+			return -1, true
+
+		case at.After(bt):
+			return 1, true
+
+		}
+
+		return 0, true
+	}
+
+	if isBigType(aval.Type()) {
+		an, aok := numeralOfValue(aval)
+		bn, bok := numeralOfValue(bval)
+		if !aok || !bok {
+			return 0, false
+		}
+
+		return an.re.Cmp(bn.re), true
+	}
+
+	switch aval.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compareOrdered(aval.Int() < bval.Int(), aval.Int() > bval.Int()), true
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return compareOrdered(aval.Uint() < bval.Uint(), aval.Uint() > bval.Uint()), true
+
+	case reflect.Float32, reflect.Float64:
+		af, bf := aval.Float(), bval.Float()
+		if af != af || bf != bf {
+			// NaN is unordered
+			return 0, false
+		}
+
+		return compareOrdered(af < bf, af > bf), true
+
+	case reflect.String:
+		return compareOrdered(aval.String() < bval.String(), aval.String() > bval.String()), true
+
+	}
+
+	return 0, false
+}
+
+func compareOrdered(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+
+	case greater:
+		return 1
+
+	}
+
+	return 0
+}
+
+// assertOrder asserts v is ordered against threshold as the operator op accepting the result of compareValues.
+func assertOrder(t TestingT, v, threshold interface{}, op string, accept func(int) bool, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	result, ok := compareValues(v, threshold)
+	if !ok {
+		exps, acts := configOf(t).toString(threshold, v)
+
+		return Errorf(t, "Parameters must be ordered values of the same type", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-expected",
+				content: exps,
+			},
+			{
+				label:   "+received",
+				content: acts,
+			},
+		}, arguments{
+			"v":         v,
+			"threshold": threshold,
+		})
+	}
+
+	if !accept(result) {
+		exps, acts := configOf(t).toString(threshold, v)
+
+		return Errorf(t, fmt.Sprintf("Expect to be %s %s", op, exps), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-expected",
+				content: op + " " + exps,
+			},
+			{
+				label:   "+received",
+				content: acts,
+			},
+		}, arguments{
+			"v":         v,
+			"threshold": threshold,
+		})
+	}
+
+	return true
+}
+
+// Greater asserts that the specified value is greater than threshold.
+//
+//	assert.Greater(t, 2, 1)
+//	assert.Greater(t, "b", "a")
+//	assert.Greater(t, time.Now(), deadline)
+//
+// Returns whether the assertion was successful (true) or not (false).
+func Greater(t TestingT, v, threshold interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	return assertOrder(t, v, threshold, ">", func(result int) bool {
+		return result > 0
+	}, extras...)
+}
+
+// GreaterOrEqual asserts that the specified value is greater than or equal to threshold.
+//
+//	assert.GreaterOrEqual(t, 2, 2)
+//
+// Returns whether the assertion was successful (true) or not (false).
+func GreaterOrEqual(t TestingT, v, threshold interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	return assertOrder(t, v, threshold, ">=", func(result int) bool {
+		return result >= 0
+	}, extras...)
+}
+
+// Less asserts that the specified value is less than threshold.
+//
+//	assert.Less(t, 1, 2)
+//	assert.Less(t, time.Second, timeout)
+//
+// Returns whether the assertion was successful (true) or not (false).
+func Less(t TestingT, v, threshold interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	return assertOrder(t, v, threshold, "<", func(result int) bool {
+		return result < 0
+	}, extras...)
+}
+
+// LessOrEqual asserts that the specified value is less than or equal to threshold.
+//
+//	assert.LessOrEqual(t, 2, 2)
+//
+// Returns whether the assertion was successful (true) or not (false).
+func LessOrEqual(t TestingT, v, threshold interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	return assertOrder(t, v, threshold, "<=", func(result int) bool {
+		return result <= 0
+	}, extras...)
+}
+
+// Between asserts that the specified value is within [min, max], both inclusive.
+//
+//	assert.Between(t, 5, 1, 10)
+//	assert.Between(t, elapsed, time.Second, 2*time.Second)
+//
+// Returns whether the assertion was successful (true) or not (false).
+func Between(t TestingT, v, min, max interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	lower, lok := compareValues(v, min)
+	upper, uok := compareValues(v, max)
+	if !lok || !uok {
+		_, acts := configOf(t).toString(nil, v)
+		mins, maxs := configOf(t).toString(min, max)
+
+		return Errorf(t, "Parameters must be ordered values of the same type", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-expected",
+				content: fmt.Sprintf("[%s, %s]", mins, maxs),
+			},
+			{
+				label:   "+received",
+				content: acts,
+			},
+		}, arguments{
+			"v":   v,
+			"min": min,
+			"max": max,
+		})
+	}
+
+	if result, _ := compareValues(min, max); result > 0 {
+		mins, maxs := configOf(t).toString(min, max)
+
+		return Errorf(t, "Range must NOT be reversed, but min is greater than max", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-expected",
+				content: fmt.Sprintf("[%s, %s]", mins, maxs),
+			},
+		}, arguments{
+			"v":   v,
+			"min": min,
+			"max": max,
+		})
+	}
+
+	if lower < 0 || upper > 0 {
+		exps, acts := configOf(t).toString(min, v)
+		_, maxs := configOf(t).toString(v, max)
+
+		return Errorf(t, fmt.Sprintf("Expect to be between %s and %s", exps, maxs), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-expected",
+				content: fmt.Sprintf("[%s, %s]", exps, maxs),
+			},
+			{
+				label:   "+received",
+				content: acts,
+			},
+		}, arguments{
+			"v":   v,
+			"min": min,
+			"max": max,
+		})
+	}
+
+	return true
+}
+
+// assertSorted asserts all adjacent elements of list are ordered by inOrder, and reports
+// the first pair out of order.
+func assertSorted(t TestingT, list interface{}, order string, inOrder func(a, b reflect.Value) (bool, error), extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	rval := reflect.ValueOf(list)
+	if rval.Kind() != reflect.Slice && rval.Kind() != reflect.Array {
+		_, acts := configOf(t).toString(nil, list)
+
+		return Errorf(t, "Parameter must be a slice or an array", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "+received",
+				content: acts,
+			},
+		}, arguments{
+			"list": list,
+		})
+	}
+
+	for i := 1; i < rval.Len(); i++ {
+		prev, next := rval.Index(i-1), rval.Index(i)
+
+		ok, err := inOrder(prev, next)
+		if err != nil {
+			_, acts := configOf(t).toString(nil, list)
+
+			return Errorf(t, err.Error(), []labeledOutput{
+				{
+					label:   labelMessages,
+					content: formatExtras(extras...),
+				},
+				{
+					label:   "+received",
+					content: acts,
+				},
+			}, arguments{
+				"list": list,
+			})
+		}
+
+		if !ok {
+			prevs, nexts := configOf(t).toString(prev.Interface(), next.Interface())
+
+			return Errorf(t, fmt.Sprintf("Expect to be %s, but [%d] and [%d] are out of order", order, i-1, i), []labeledOutput{
+				{
+					label:   labelMessages,
+					content: formatExtras(extras...),
+				},
+				{
+					label:   fmt.Sprintf("-[%d]", i-1),
+					content: prevs,
+				},
+				{
+					label:   fmt.Sprintf("+[%d]", i),
+					content: nexts,
+				},
+			}, arguments{
+				"list": list,
+			})
+		}
+	}
+
+	return true
+}
+
+// IsSorted asserts that elements of the specified slice or array are in non-decreasing order.
+//
+//	assert.IsSorted(t, []int{1, 2, 2, 3})
+//
+// Returns whether the assertion was successful (true) or not (false).
+func IsSorted(t TestingT, list interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	return assertSorted(t, list, "sorted", func(a, b reflect.Value) (bool, error) {
+		result, ok := compareReflectValues(a, b)
+		if !ok {
+			return false, fmt.Errorf("Elements must be ordered values, but got %s", a.Type())
+		}
+
+		return result <= 0, nil
+	}, extras...)
+}
+
+// IsStrictlyIncreasing asserts that elements of the specified slice or array are in increasing order,
+// which means no duplicated elements.
+//
+//	assert.IsStrictlyIncreasing(t, []int{1, 2, 3})
+//
+// Returns whether the assertion was successful (true) or not (false).
+func IsStrictlyIncreasing(t TestingT, list interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	return assertSorted(t, list, "strictly increasing", func(a, b reflect.Value) (bool, error) {
+		result, ok := compareReflectValues(a, b)
+		if !ok {
+			return false, fmt.Errorf("Elements must be ordered values, but got %s", a.Type())
+		}
+
+		return result < 0, nil
+	}, extras...)
+}
+
+// IsSortedBy asserts that elements of the specified slice or array are sorted by less, which
+// MUST be a func(a, b T) bool accepting elements of the list.
+//
+//	assert.IsSortedBy(t, users, func(a, b User) bool {
+//	    return a.Name < b.Name
+//	})
+//
+// Returns whether the assertion was successful (true) or not (false).
+func IsSortedBy(t TestingT, list, less interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	fn := reflect.ValueOf(less)
+	if fn.Kind() != reflect.Func || fn.IsNil() ||
+		fn.Type().NumIn() != 2 || fn.Type().NumOut() != 1 || fn.Type().Out(0).Kind() != reflect.Bool {
+		return Errorf(t, fmt.Sprintf("Less must be a func(a, b T) bool, but got %T", less), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+		}, arguments{
+			"list": list,
+			"less": less,
+		})
+	}
+
+	ftype := fn.Type()
+
+	return assertSorted(t, list, "sorted", func(a, b reflect.Value) (bool, error) {
+		// elements of interfaces are passed by their dynamic values
+		if a.Kind() == reflect.Interface && !a.IsNil() {
+			a = a.Elem()
+		}
+		if b.Kind() == reflect.Interface && !b.IsNil() {
+			b = b.Elem()
+		}
+
+		for _, v := range []reflect.Value{a, b} {
+			if !v.Type().AssignableTo(ftype.In(0)) || !v.Type().AssignableTo(ftype.In(1)) {
+				return false, fmt.Errorf("Less must be a func(a, b %s) bool, but got %T", v.Type(), less)
+			}
+		}
+
+		// sorted if b is NOT less than a
+		return !fn.Call([]reflect.Value{b, a})[0].Bool(), nil
+	}, extras...)
+}
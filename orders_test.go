@@ -0,0 +1,177 @@
+package gospec
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+type testingLevel int
+
+func TestGreater(t *testing.T) {
+	mockT := new(testing.T)
+
+	now := time.Now()
+
+	trueCases := []struct {
+		v, threshold interface{}
+	}{
+		{2, 1},
+		{int8(2), int8(1)},
+		{uint64(1 << 63), uint64(1<<63 - 1)},
+		{int64(1<<62 + 1), int64(1 << 62)},
+		{float32(1.5), float32(1.25)},
+		{"b", "a"},
+		{2 * time.Second, time.Second},
+		{now.Add(time.Nanosecond), now},
+		{testingLevel(2), testingLevel(1)},
+	}
+
+	for i, tc := range trueCases {
+		True(t, Greater(mockT, tc.v, tc.threshold), "Greater should return true for trueCases(%d)", i)
+		True(t, GreaterOrEqual(mockT, tc.v, tc.threshold), "GreaterOrEqual should return true for trueCases(%d)", i)
+		True(t, Less(mockT, tc.threshold, tc.v), "Less should return true for trueCases(%d)", i)
+		True(t, LessOrEqual(mockT, tc.threshold, tc.v), "LessOrEqual should return true for trueCases(%d)", i)
+
+		False(t, Greater(mockT, tc.threshold, tc.v), "Greater should return false for trueCases(%d)", i)
+		False(t, Less(mockT, tc.v, tc.threshold), "Less should return false for trueCases(%d)", i)
+	}
+
+	falseCases := []struct {
+		v, threshold interface{}
+	}{
+		{1, 1},
+		{1, int64(0)},
+		{nil, 0},
+		{[]int{1}, []int{0}},
+		{struct{}{}, struct{}{}},
+		{testingLevel(1), 0},
+	}
+
+	for i, fc := range falseCases {
+		False(t, Greater(mockT, fc.v, fc.threshold), "Greater should return false for falseCases(%d)", i)
+		False(t, Less(mockT, fc.v, fc.threshold), "Less should return false for falseCases(%d)", i)
+	}
+
+	True(t, GreaterOrEqual(mockT, 1, 1))
+	True(t, LessOrEqual(mockT, "a", "a"))
+}
+
+func TestBetween(t *testing.T) {
+	mockT := new(testing.T)
+
+	True(t, Between(mockT, 5, 1, 10))
+	True(t, Between(mockT, 1, 1, 10))
+	True(t, Between(mockT, 10, 1, 10))
+	True(t, Between(mockT, 1500*time.Millisecond, time.Second, 2*time.Second))
+	True(t, Between(mockT, "b", "a", "c"))
+
+	False(t, Between(mockT, 0, 1, 10))
+	False(t, Between(mockT, 11, 1, 10))
+	False(t, Between(mockT, 5, 1.0, 10.0))
+	False(t, Between(mockT, nil, 1, 10))
+
+	rt := new(RecordingT)
+
+	False(t, Between(rt, 11, 1, 10))
+
+	records := rt.Records()
+	if Len(t, records, 1) {
+		Equal(t, "[1, 10]", records[0].Expected)
+	}
+
+	// reversed ranges are usage errors
+	rt.Reset()
+
+	False(t, Between(rt, 5, 10, 1))
+
+	records = rt.Records()
+	if Len(t, records, 1) {
+		Equal(t, "Range must NOT be reversed, but min is greater than max", records[0].Error)
+		Equal(t, "[10, 1]", records[0].Expected)
+	}
+}
+
+func TestIsSorted(t *testing.T) {
+	mockT := new(testing.T)
+
+	True(t, IsSorted(mockT, []int{}))
+	True(t, IsSorted(mockT, []int{1}))
+	True(t, IsSorted(mockT, []int{1, 2, 2, 3}))
+	True(t, IsSorted(mockT, [3]string{"a", "b", "c"}))
+	True(t, IsSorted(mockT, []time.Duration{time.Second, time.Minute}))
+
+	False(t, IsSorted(mockT, []int{1, 3, 2}))
+	False(t, IsSorted(mockT, []struct{}{{}, {}}))
+	False(t, IsSorted(mockT, 1))
+	False(t, IsSorted(mockT, nil))
+
+	// elements of interfaces are compared by their dynamic values
+	True(t, IsSorted(mockT, []interface{}{1, 2, 3}))
+	False(t, IsSorted(mockT, []interface{}{1, 3, 2}))
+	False(t, IsSorted(mockT, []interface{}{1, "2"}))
+	False(t, IsSorted(mockT, []interface{}{1, nil}))
+	True(t, IsStrictlyIncreasing(mockT, []fmt.Stringer{time.Second, time.Minute}))
+
+	True(t, IsStrictlyIncreasing(mockT, []int{1, 2, 3}))
+	False(t, IsStrictlyIncreasing(mockT, []int{1, 2, 2, 3}))
+	False(t, IsStrictlyIncreasing(mockT, []float64{1, 0.5}))
+}
+
+func TestIsSortedBy(t *testing.T) {
+	mockT := new(testing.T)
+
+	type user struct {
+		Name string
+		Age  int
+	}
+
+	users := []user{
+		{"alice", 30},
+		{"bob", 20},
+		{"carol", 20},
+	}
+
+	True(t, IsSortedBy(mockT, users, func(a, b user) bool {
+		return a.Name < b.Name
+	}))
+	False(t, IsSortedBy(mockT, users, func(a, b user) bool {
+		return a.Age < b.Age
+	}))
+
+	False(t, IsSortedBy(mockT, users, nil))
+	False(t, IsSortedBy(mockT, users, func(a, b int) bool {
+		return a < b
+	}))
+	False(t, IsSortedBy(mockT, users, func(a user) bool {
+		return true
+	}))
+
+	// less is checked even if there is no pair to compare
+	less := func(a, b int) bool {
+		return a < b
+	}
+
+	True(t, IsSortedBy(mockT, []int{}, less))
+	True(t, IsSortedBy(mockT, []int{1}, less))
+	False(t, IsSortedBy(mockT, []int{}, "not a func"))
+	False(t, IsSortedBy(mockT, []int{1}, "not a func"))
+	False(t, IsSortedBy(mockT, []int{1}, func(a, b int) int {
+		return a - b
+	}))
+
+	// nil funcs are rejected rather than called
+	var nilLess func(a, b int) bool
+
+	rt := NewRecordingT("TestIsSortedBy")
+
+	False(t, IsSortedBy(rt, []int{1, 2}, nilLess))
+	if records := rt.Records(); Len(t, records, 1) {
+		Equal(t, "Less must be a func(a, b T) bool, but got func(int, int) bool", records[0].Error)
+	}
+
+	// elements of interfaces are passed by their dynamic values
+	True(t, IsSortedBy(mockT, []interface{}{1, 2, 3}, less))
+	False(t, IsSortedBy(mockT, []interface{}{1, 3, 2}, less))
+	False(t, IsSortedBy(mockT, []interface{}{1, "2"}, less))
+}
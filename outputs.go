@@ -26,6 +26,7 @@ type labeledOutput struct {
 type testingOutput struct {
 	labels  []labeledOutput
 	padding int
+	config  *Config
 }
 
 func (output *testingOutput) Add(label labeledOutput) *testingOutput {
@@ -40,6 +41,26 @@ func (output *testingOutput) Padding(padding int) *testingOutput {
 	return output
 }
 
+func (output *testingOutput) Config(config *Config) *testingOutput {
+	output.config = config
+
+	return output
+}
+
+func (output *testingOutput) getConfig() *Config {
+	if output.config == nil {
+		c := GetConfig()
+
+		output.config = &c
+	}
+
+	return output.config
+}
+
+func (output *testingOutput) newLine() string {
+	return output.getConfig().newLine()
+}
+
 func (output *testingOutput) LongestLabelLen() int {
 	longestLabel := 0
 	for _, label := range output.labels {
@@ -62,7 +83,7 @@ func (output *testingOutput) formatMessages(message string, longestLabelLen int)
 		// no need to align first line because it starts at the correct location (after the label)
 		if i != 0 {
 			// append alignLen+1 spaces to align with "{{longestLabel}}:" before adding tab
-			buf.WriteString(labelNewLine + strings.Repeat(" ", longestLabelLen+1) + "\t")
+			buf.WriteString(output.newLine() + strings.Repeat(" ", longestLabelLen+1) + "\t")
 		}
 
 		buf.WriteString(scanner.Text())
@@ -89,14 +110,19 @@ func (output *testingOutput) String() string {
 			continue
 		}
 
-		nl := labelNewLine
+		nl := output.newLine()
 		if output.padding-len(label.label) > 0 {
 			nl += strings.Repeat(" ", output.padding-len(label.label))
 		}
 
+		content := label.content
+		if label.label == labelDiff {
+			content = renderDiff(content, output.getConfig())
+		}
+
 		s += nl
 		s += label.label + ":"
-		s += "\t" + output.formatMessages(strings.Replace(label.content, labelNewLine, nl, -1), output.padding)
+		s += "\t" + output.formatMessages(strings.Replace(content, output.newLine(), nl, -1), output.padding)
 	}
 
 	return s + "\n\r"
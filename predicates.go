@@ -0,0 +1,371 @@
+package gospec
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// entry is an element of a collection with its index or key.
+type entry struct {
+	key   reflect.Value
+	value reflect.Value
+	name  string
+}
+
+// entriesOf returns elements of a slice, an array, a map or a buffered channel. Elements received
+// from the channel are sent back in order, so the channel is left as is unless it's closed.
+// NOTE: elements of receive-only channels can't be sent back, so they're consumed.
+func entriesOf(c *Config, v interface{}) ([]entry, bool) {
+	rval := reflect.ValueOf(v)
+
+	switch rval.Kind() {
+	case reflect.Slice, reflect.Array:
+		entries := make([]entry, rval.Len())
+		for i := range entries {
+			entries[i] = entry{
+				key:   reflect.ValueOf(i),
+				value: rval.Index(i),
+				name:  fmt.Sprintf("[%d]", i),
+			}
+		}
+
+		return entries, true
+
+	case reflect.Map:
+		keys := sortedKeys(rval)
+
+		entries := make([]entry, len(keys))
+		for i, key := range keys {
+			ks, _ := c.toString(key.Interface(), key.Interface())
+
+			entries[i] = entry{
+				key:   key,
+				value: rval.MapIndex(key),
+				name:  "[" + ks + "]",
+			}
+		}
+
+		return entries, true
+
+	case reflect.Chan:
+		if rval.Type().ChanDir()&reflect.RecvDir == 0 {
+			return nil, false
+		}
+
+		n := rval.Len()
+
+		entries := make([]entry, 0, n)
+		for i := 0; i < n; i++ {
+			value, ok := rval.TryRecv()
+			if !ok {
+				break
+			}
+
+			entries = append(entries, entry{
+				key:   reflect.ValueOf(i),
+				value: value,
+				name:  fmt.Sprintf("[%d]", i),
+			})
+		}
+
+		if rval.Type().ChanDir() == reflect.BothDir {
+			for _, e := range entries {
+				if !sendBack(rval, e.value) {
+					break
+				}
+			}
+		}
+
+		return entries, true
+
+	}
+
+	return nil, false
+}
+
+// sendBack sends value to the channel, and returns false if the channel is closed or full.
+func sendBack(ch, value reflect.Value) (ok bool) {
+	defer func() {
+		if e := recover(); e != nil {
+			ok = false
+		}
+	}()
+
+	return ch.TrySend(value)
+}
+
+// entryTypesOf returns types of keys and elements of entries of a collection of ctype.
+func entryTypesOf(ctype reflect.Type) (key, value reflect.Type) {
+	if ctype.Kind() == reflect.Map {
+		return ctype.Key(), ctype.Elem()
+	}
+
+	return reflect.TypeOf(0), ctype.Elem()
+}
+
+// predicateOf returns a func calling pred, which MUST be a func(value) bool or func(key, value) bool
+// accepting elements of entries of a collection of ctype. It's checked against ctype even if
+// there're no entries, and against dynamic values of entries if elements are interfaces.
+func predicateOf(pred interface{}, ctype reflect.Type, entries []entry) (func(e entry) bool, error) {
+	fn := reflect.ValueOf(pred)
+	if fn.Kind() != reflect.Func || fn.IsNil() ||
+		(fn.Type().NumIn() != 1 && fn.Type().NumIn() != 2) ||
+		fn.Type().NumOut() != 1 || fn.Type().Out(0).Kind() != reflect.Bool {
+		return nil, fmt.Errorf("Predicate must be a func(value) bool or func(key, value) bool, but got %T", pred)
+	}
+
+	ftype := fn.Type()
+
+	accepts := func(typ, in reflect.Type) bool {
+		return typ.Kind() == reflect.Interface || typ.AssignableTo(in)
+	}
+
+	keyType, valueType := entryTypesOf(ctype)
+	if ftype.NumIn() == 2 && !accepts(keyType, ftype.In(0)) {
+		return nil, fmt.Errorf("Predicate must accept keys of %s, but got %T", keyType, pred)
+	}
+	if !accepts(valueType, ftype.In(ftype.NumIn()-1)) {
+		return nil, fmt.Errorf("Predicate must accept elements of %s, but got %T", valueType, pred)
+	}
+
+	arg := func(v reflect.Value, in reflect.Type) (reflect.Value, bool) {
+		if v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				switch in.Kind() {
+				case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+					return reflect.Zero(in), true
+				}
+
+				return v, false
+			}
+
+			v = v.Elem()
+		}
+
+		return v, v.Type().AssignableTo(in)
+	}
+
+	for _, e := range entries {
+		if ftype.NumIn() == 2 {
+			if _, ok := arg(e.key, ftype.In(0)); !ok {
+				return nil, fmt.Errorf("Predicate must accept keys of %s, but got %T", e.key.Type(), pred)
+			}
+		}
+
+		if _, ok := arg(e.value, ftype.In(ftype.NumIn()-1)); !ok {
+			return nil, fmt.Errorf("Predicate must accept elements of %s, but got %T", e.value.Type(), pred)
+		}
+	}
+
+	return func(e entry) bool {
+		args := make([]reflect.Value, 0, 2)
+		if ftype.NumIn() == 2 {
+			key, _ := arg(e.key, ftype.In(0))
+
+			args = append(args, key)
+		}
+
+		value, _ := arg(e.value, ftype.In(ftype.NumIn()-1))
+		args = append(args, value)
+
+		return fn.Call(args)[0].Bool()
+	}, nil
+}
+
+// partitionEntries returns entries of v satisfying pred and the others.
+func partitionEntries(t TestingT, v, pred interface{}, extras ...interface{}) (matched, unmatched []entry, ok bool) {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	entries, ok := entriesOf(configOf(t), v)
+	if !ok {
+		_, acts := configOf(t).toString(nil, v)
+
+		return nil, nil, Errorf(t, "Parameter must be a slice, an array, a map or a channel", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "+received",
+				content: acts,
+			},
+		}, arguments{
+			"v":    v,
+			"pred": pred,
+		})
+	}
+
+	fn, err := predicateOf(pred, reflect.TypeOf(v), entries)
+	if err != nil {
+		return nil, nil, Errorf(t, err.Error(), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+		}, arguments{
+			"v":    v,
+			"pred": pred,
+		})
+	}
+
+	for _, e := range entries {
+		if fn(e) {
+			matched = append(matched, e)
+		} else {
+			unmatched = append(unmatched, e)
+		}
+	}
+
+	return matched, unmatched, true
+}
+
+// formatEntryList returns entries one per line with their indexes or keys.
+func (c *Config) formatEntryList(entries []entry) string {
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = e.name + " " + c.valueString(e.value)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Every asserts that all elements of the specified slice, array, map or channel satisfy pred,
+// which is a func(value) bool, or a func(key, value) bool receiving indexes or keys too.
+// NOTE: elements of receive-only channels are consumed, while others are sent back.
+//
+//	assert.Every(t, []int{2, 4, 6}, func(n int) bool {
+//		return n%2 == 0
+//	})
+//
+// Returns whether the assertion was successful (true) or not (false).
+func Every(t TestingT, v, pred interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	matched, unmatched, ok := partitionEntries(t, v, pred, extras...)
+	if !ok {
+		return false
+	}
+
+	if len(unmatched) > 0 {
+		return Errorf(t, fmt.Sprintf("Expect every element to satisfy the predicate, but %d of %d violate", len(unmatched), len(matched)+len(unmatched)), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "+violations",
+				content: configOf(t).formatEntryList(unmatched),
+			},
+		}, arguments{
+			"v":    v,
+			"pred": pred,
+		})
+	}
+
+	return true
+}
+
+// Any asserts that at least one element of the specified slice, array, map or channel satisfies pred,
+// which is a func(value) bool, or a func(key, value) bool receiving indexes or keys too.
+//
+//	assert.Any(t, users, func(u User) bool {
+//		return u.Admin
+//	})
+//
+// Returns whether the assertion was successful (true) or not (false).
+func Any(t TestingT, v, pred interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	_, ok := Find(t, v, pred, extras...)
+
+	return ok
+}
+
+// None asserts that no element of the specified slice, array, map or channel satisfies pred,
+// which is a func(value) bool, or a func(key, value) bool receiving indexes or keys too.
+//
+//	assert.None(t, []string{"a", "b"}, func(s string) bool {
+//		return s == ""
+//	})
+//
+// Returns whether the assertion was successful (true) or not (false).
+func None(t TestingT, v, pred interface{}, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	matched, unmatched, ok := partitionEntries(t, v, pred, extras...)
+	if !ok {
+		return false
+	}
+
+	if len(matched) > 0 {
+		return Errorf(t, fmt.Sprintf("Expect no element to satisfy the predicate, but %d of %d satisfy", len(matched), len(matched)+len(unmatched)), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "+violations",
+				content: configOf(t).formatEntryList(matched),
+			},
+		}, arguments{
+			"v":    v,
+			"pred": pred,
+		})
+	}
+
+	return true
+}
+
+// Find asserts that at least one element of the specified slice, array, map or channel satisfies pred,
+// and returns the first one, which is ordered by keys for maps.
+//
+//	admin, ok := assert.Find(t, users, func(u User) bool {
+//		return u.Admin
+//	})
+//
+// Returns the element found and whether the assertion was successful (true) or not (false).
+func Find(t TestingT, v, pred interface{}, extras ...interface{}) (interface{}, bool) {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	matched, unmatched, ok := partitionEntries(t, v, pred, extras...)
+	if !ok {
+		return nil, false
+	}
+
+	if len(matched) == 0 {
+		labels := []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+		}
+		if len(unmatched) > 0 {
+			labels = append(labels, labeledOutput{
+				label:   "+elements",
+				content: configOf(t).formatEntryList(unmatched),
+			})
+		}
+
+		return nil, Errorf(t, fmt.Sprintf("Expect any element to satisfy the predicate, but none of %d satisfies", len(unmatched)), labels, arguments{
+			"v":    v,
+			"pred": pred,
+		})
+	}
+
+	if !matched[0].value.CanInterface() {
+		return nil, true
+	}
+
+	return matched[0].value.Interface(), true
+}
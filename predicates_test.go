@@ -0,0 +1,123 @@
+package gospec
+
+import (
+	"testing"
+)
+
+func TestEvery(t *testing.T) {
+	mockT := new(testing.T)
+
+	even := func(n int) bool {
+		return n%2 == 0
+	}
+
+	True(t, Every(mockT, []int{}, even))
+	True(t, Every(mockT, []int{2, 4, 6}, even))
+	True(t, Every(mockT, [2]int{2, 4}, even))
+	True(t, Every(mockT, map[string]int{"a": 2}, even))
+	True(t, Every(mockT, []interface{}{2, 4}, even))
+	True(t, Every(mockT, map[string]int{"a": 2}, func(key string, n int) bool {
+		return key == "a"
+	}))
+	True(t, Every(mockT, []int{0, 1, 2}, func(i, n int) bool {
+		return i == n
+	}))
+	False(t, Every(mockT, []int{2, 3, 6}, even))
+	False(t, Every(mockT, []string{"a"}, even))
+	False(t, Every(mockT, []int{2}, func(n int) {}))
+	False(t, Every(mockT, []int{2}, nil))
+	False(t, Every(mockT, []string{}, even))
+	False(t, Every(mockT, map[string]int{}, func(key int, n int) bool {
+		return true
+	}))
+	True(t, Every(mockT, []interface{}{}, even))
+	False(t, Every(mockT, 2, even))
+
+	rt := new(RecordingT)
+
+	False(t, Every(rt, map[string]int{"a": 1, "b": 2, "c": 3}, even))
+
+	records := rt.Records()
+	if Len(t, records, 1) {
+		Contains(t, records[0].Error, "but 2 of 3 violate")
+		Equal(t, "[\"a\"] 1\n[\"c\"] 3", testingLabelOf(records[0], "+violations"))
+	}
+}
+
+func TestEveryWithChannel(t *testing.T) {
+	mockT := new(testing.T)
+
+	ch := make(chan int, 3)
+	ch <- 2
+	ch <- 3
+
+	False(t, Every(mockT, ch, func(n int) bool {
+		return n%2 == 0
+	}))
+	True(t, Any(mockT, ch, func(n int) bool {
+		return n == 3
+	}))
+
+	// elements are sent back in order
+	Equal(t, 2, len(ch))
+	Equal(t, 2, <-ch)
+	Equal(t, 3, <-ch)
+
+	// elements of receive-only channels are consumed
+	var recvCh <-chan int = ch
+	ch <- 4
+
+	True(t, Every(mockT, recvCh, func(n int) bool {
+		return n == 4
+	}))
+	Equal(t, 0, len(ch))
+
+	False(t, Every(mockT, make(chan<- int), func(n int) bool {
+		return true
+	}))
+}
+
+func TestAny(t *testing.T) {
+	mockT := new(testing.T)
+
+	empty := func(s string) bool {
+		return s == ""
+	}
+
+	True(t, Any(mockT, []string{"a", ""}, empty))
+	False(t, Any(mockT, []string{"a", "b"}, empty))
+	False(t, Any(mockT, []string{}, empty))
+
+	True(t, None(mockT, []string{"a", "b"}, empty))
+	True(t, None(mockT, []string{}, empty))
+	False(t, None(mockT, []string{"a", ""}, empty))
+
+	found, ok := Find(mockT, map[string]string{"b": "", "a": ""}, func(key, value string) bool {
+		return value == ""
+	})
+	True(t, ok)
+	Equal(t, "", found)
+
+	found, ok = Find(mockT, []interface{}{nil, 1, "s"}, func(v interface{}) bool {
+		return v != nil
+	})
+	True(t, ok)
+	Equal(t, 1, found)
+
+	found, ok = Find(mockT, []int{1, 3}, func(n int) bool {
+		return n%2 == 0
+	})
+	False(t, ok)
+	Nil(t, found)
+
+	// elements of empty collections are omitted
+	rt := NewRecordingT("TestAny")
+
+	False(t, Any(rt, []string{}, empty))
+	if records := rt.Records(); Len(t, records, 1) {
+		Equal(t, "Expect any element to satisfy the predicate, but none of 0 satisfies", records[0].Error)
+		for _, label := range records[0].Labels {
+			NotEqual(t, "+elements", label.Label)
+		}
+	}
+}
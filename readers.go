@@ -0,0 +1,681 @@
+package gospec
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// readerChunkSize is the size of chunks read from readers while searching.
+	readerChunkSize = 32 * 1024
+
+	// readerSpoolSize is the max size of consumed data kept in memory for replaying,
+	// larger data is spooled to a temp file.
+	readerSpoolSize = 1 << 20
+)
+
+// needleOf returns the bytes of a string or []byte, or the format of other values.
+func needleOf(v interface{}) []byte {
+	switch s := v.(type) {
+	case string:
+		return []byte(s)
+
+	case []byte:
+		return s
+
+	}
+
+	return []byte(fmt.Sprint(v))
+}
+
+// searchReader reads r by chunks until needle is found or EOF, and writes all bytes read to w if it's not nil.
+// Only len(needle)-1 bytes are kept between chunks, so memory is bounded regardless of size of r.
+// It returns the number of bytes read.
+func searchReader(r io.Reader, needle []byte, w io.Writer) (found bool, n int64, err error) {
+	if len(needle) == 0 {
+		return true, 0, nil
+	}
+
+	window := make([]byte, 0, len(needle)-1+readerChunkSize)
+	chunk := make([]byte, readerChunkSize)
+	for {
+		read, rerr := r.Read(chunk)
+		if read > 0 {
+			n += int64(read)
+
+			if w != nil {
+				if _, werr := w.Write(chunk[:read]); werr != nil {
+					return false, n, werr
+				}
+			}
+
+			window = append(window, chunk[:read]...)
+			if bytes.Contains(window, needle) {
+				return true, n, nil
+			}
+
+			// keep the tail which may be the prefix of needle
+			if tail := len(needle) - 1; len(window) > tail {
+				window = append(window[:0], window[len(window)-tail:]...)
+			}
+		}
+
+		if rerr == io.EOF {
+			return false, n, nil
+		}
+		if rerr != nil {
+			return false, n, rerr
+		}
+	}
+}
+
+// searchReaderAll reads r by chunks until all needles are found or EOF. Only the bytes of the longest
+// needle minus 1 are kept between chunks, so memory is bounded regardless of size of r.
+// It returns which of needles are found, and the number of bytes read.
+func searchReaderAll(r io.Reader, needles [][]byte) (found []bool, n int64, err error) {
+	found = make([]bool, len(needles))
+
+	longest, missing := 0, 0
+	for i, needle := range needles {
+		if len(needle) == 0 {
+			found[i] = true
+			continue
+		}
+
+		if len(needle) > longest {
+			longest = len(needle)
+		}
+		missing++
+	}
+	if missing == 0 {
+		return found, 0, nil
+	}
+
+	window := make([]byte, 0, longest-1+readerChunkSize)
+	chunk := make([]byte, readerChunkSize)
+	for {
+		read, rerr := r.Read(chunk)
+		if read > 0 {
+			n += int64(read)
+
+			window = append(window, chunk[:read]...)
+			for i, needle := range needles {
+				if !found[i] && bytes.Contains(window, needle) {
+					found[i] = true
+					missing--
+				}
+			}
+			if missing == 0 {
+				return found, n, nil
+			}
+
+			// keep the tail which may be the prefix of any needle
+			if tail := longest - 1; len(window) > tail {
+				window = append(window[:0], window[len(window)-tail:]...)
+			}
+		}
+
+		if rerr == io.EOF {
+			return found, n, nil
+		}
+		if rerr != nil {
+			return found, n, rerr
+		}
+	}
+}
+
+// searchable returns whether r can be searched without consuming it, i.e. it exposes its unread bytes
+// by Bytes(), or it's an io.Seeker able to rewind.
+func searchable(r io.Reader) bool {
+	if _, ok := r.(interface{ Bytes() []byte }); ok {
+		return true
+	}
+
+	if seeker, ok := r.(io.Seeker); ok {
+		_, err := seeker.Seek(0, io.SeekCurrent)
+		return err == nil
+	}
+
+	return false
+}
+
+// containsReader returns which of elements r contains, which is searched once for all of them.
+//
+// Buffers exposing their unread bytes, e.g. *bytes.Buffer, are searched without reading.
+// io.Seeker readers are rewound to their current offsets, others are consumed by the search.
+func containsReader(r io.Reader, elements ...interface{}) ([]bool, error) {
+	needles := make([][]byte, len(elements))
+	for i, element := range elements {
+		needles[i] = needleOf(element)
+	}
+
+	if buf, ok := r.(interface{ Bytes() []byte }); ok {
+		found := make([]bool, len(needles))
+		for i, needle := range needles {
+			found[i] = bytes.Contains(buf.Bytes(), needle)
+		}
+
+		return found, nil
+	}
+
+	if seeker, ok := r.(io.Seeker); ok {
+		offset, err := seeker.Seek(0, io.SeekCurrent)
+		if err == nil {
+			found, _, err := searchReaderAll(r, needles)
+
+			if _, serr := seeker.Seek(offset, io.SeekStart); serr != nil && err == nil {
+				err = fmt.Errorf("CANNOT rewind io.Reader: %v", serr)
+			}
+
+			return found, err
+		}
+	}
+
+	found, _, err := searchReaderAll(r, needles)
+
+	return found, err
+}
+
+// containsAllOf returns which of elements v contains. io.Reader values are searched once for all of them,
+// and it fails if v can't be searched without consuming it, which must be searched by ReaderContains
+// instead, or reading v fails.
+func containsAllOf(t TestingT, v interface{}, elements []interface{}, extras []interface{}, args arguments) ([]bool, bool) {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	r, ok := v.(io.Reader)
+	if !ok {
+		found := make([]bool, len(elements))
+		for i, element := range elements {
+			found[i] = ContainsElement(v, element)
+		}
+
+		return found, true
+	}
+
+	if !searchable(r) {
+		return nil, Errorf(t, fmt.Sprintf("Parameter must be an io.Seeker or expose Bytes() to be searched without consuming it, but got %T, use ReaderContains instead", v), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+		}, args)
+	}
+
+	found, err := containsReader(r, elements...)
+	if err != nil {
+		return nil, Errorf(t, fmt.Sprintf("Expect to read io.Reader, but failed: %v", err), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+		}, args)
+	}
+
+	return found, true
+}
+
+// containsOf returns whether v contains element, see containsAllOf.
+func containsOf(t TestingT, v, element interface{}, extras []interface{}, args arguments) (contained, ok bool) {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	found, ok := containsAllOf(t, v, []interface{}{element}, extras, args)
+	if !ok {
+		return false, false
+	}
+
+	return found[0], true
+}
+
+// spool keeps data in memory, and moves it to a temp file once it exceeds readerSpoolSize. The dir
+// of the temp file is resolved by dir then, since it may be created on demand, e.g. TempDir of t.
+type spool struct {
+	buf  bytes.Buffer
+	dir  func() string
+	file *os.File
+}
+
+func (s *spool) Write(p []byte) (int, error) {
+	if s.file == nil && s.buf.Len()+len(p) > readerSpoolSize {
+		dir := ""
+		if s.dir != nil {
+			dir = s.dir()
+		}
+
+		file, err := os.CreateTemp(dir, "gospec-spool-")
+		if err != nil {
+			// keep in memory if no temp file available
+			return s.buf.Write(p)
+		}
+
+		if _, err := file.Write(s.buf.Bytes()); err != nil {
+			file.Close()
+			os.Remove(file.Name())
+
+			return 0, err
+		}
+
+		s.buf.Reset()
+		s.file = file
+	}
+
+	if s.file != nil {
+		return s.file.Write(p)
+	}
+
+	return s.buf.Write(p)
+}
+
+// reader returns a reader of all data written.
+func (s *spool) reader() (io.Reader, error) {
+	if s.file == nil {
+		return &s.buf, nil
+	}
+
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	return s.file, nil
+}
+
+func (s *spool) Close() error {
+	if s.file == nil {
+		return nil
+	}
+
+	file := s.file
+	s.file = nil
+
+	file.Close()
+
+	return os.Remove(file.Name())
+}
+
+// replayReader reads data consumed from the origin reader first, and then the rest of the origin.
+type replayReader struct {
+	io.Reader
+
+	spool  *spool
+	origin io.Reader
+}
+
+// Close removes the spooled data, and closes the origin reader if it's an io.Closer.
+func (r *replayReader) Close() error {
+	err := r.spool.Close()
+
+	if closer, ok := r.origin.(io.Closer); ok {
+		if cerr := closer.Close(); cerr != nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
+
+// ReaderContains asserts that the specified reader contains substr, which is a string or []byte.
+// The reader is streamed by chunks until substr is found, so it works with huge inputs.
+//
+// The data read is handed back by the returned reader, which replays it before the rest of r,
+// and closes r on Close if r is an io.Closer. The data is spooled to a temp file if it's large,
+// which is created in TempDir of t and removed by Cleanup of t if they're supported.
+//
+//	resp.Body, ok = assert.ReaderContains(t, resp.Body, `"status":"ok"`)
+//
+// Returns the replacement reader and whether the assertion was successful (true) or not (false).
+func ReaderContains(t TestingT, r io.Reader, substr interface{}, extras ...interface{}) (io.ReadCloser, bool) {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	needle := needleOf(substr)
+
+	s := &spool{
+		dir: func() string {
+			return tempDirOf(t)
+		},
+	}
+	cleanupOf(t, func() {
+		s.Close()
+	})
+
+	found, n, err := searchReader(r, needle, s)
+
+	replay := &replayReader{
+		Reader: r,
+		spool:  s,
+		origin: r,
+	}
+	if sr, serr := s.reader(); serr == nil {
+		replay.Reader = io.MultiReader(sr, r)
+	} else if err == nil {
+		err = serr
+	}
+
+	if err != nil {
+		return replay, Errorf(t, fmt.Sprintf("Expect to read io.Reader, but failed: %v", err), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "+received",
+				content: fmt.Sprintf("%d byte(s) read from %T", n, r),
+			},
+		}, arguments{
+			"r":      r,
+			"substr": substr,
+		})
+	}
+
+	if !found {
+		return replay, Errorf(t, "Expect to include substring", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-substring",
+				content: fmt.Sprintf("%q", needle),
+			},
+			{
+				label:   "+received",
+				content: fmt.Sprintf("%d byte(s) read from %T", n, r),
+			},
+		}, arguments{
+			"r":      r,
+			"substr": substr,
+		})
+	}
+
+	return replay, true
+}
+
+// maxContextLineLen is the max number of bytes of each context line reported for differing streams.
+const maxContextLineLen = 256
+
+// streamPosition tracks the position of bytes compared, with lines before the current one for context.
+type streamPosition struct {
+	offset  int64
+	line    int
+	column  int
+	current []byte
+	lines   []string
+	context int
+}
+
+// advance moves the position over data.
+func (p *streamPosition) advance(data []byte) {
+	for len(data) > 0 {
+		part := data
+		i := bytes.IndexByte(data, '\n')
+		if i >= 0 {
+			part = data[:i]
+		}
+
+		if room := maxContextLineLen - len(p.current); room > 0 {
+			if room > len(part) {
+				room = len(part)
+			}
+
+			p.current = append(p.current, part[:room]...)
+		}
+		p.offset += int64(len(part))
+		p.column += len(part)
+
+		if i < 0 {
+			break
+		}
+
+		p.offset++
+		if p.context > 0 {
+			p.lines = append(p.lines, string(p.current))
+			if len(p.lines) > p.context {
+				p.lines = p.lines[1:]
+			}
+		}
+		p.current = p.current[:0]
+		p.line++
+		p.column = 0
+
+		data = data[i+1:]
+	}
+}
+
+// excerpt returns lines around the position, the rest of which are read from r.
+func (p *streamPosition) excerpt(r io.Reader) string {
+	br := bufio.NewReader(r)
+
+	lines := make([]string, 0, 2*p.context+2)
+	for i, line := range p.lines {
+		lines = append(lines, fmt.Sprintf("%6d | %s", p.line-len(p.lines)+i, contextLine([]byte(line))))
+	}
+
+	for i := 0; i <= p.context; i++ {
+		line, err := readContextLine(br)
+		if i == 0 {
+			line = append(append([]byte{}, p.current...), line...)
+		}
+
+		if len(line) > 0 || err == nil || i == 0 {
+			lines = append(lines, fmt.Sprintf("%6d | %s", p.line+i, contextLine(line)))
+		}
+
+		if err != nil {
+			lines = append(lines, "       | <EOF>")
+			break
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// readContextLine reads a line without the line break from r, only the first maxContextLineLen bytes are kept.
+func readContextLine(r *bufio.Reader) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if room := maxContextLineLen - len(line); room > 0 {
+			if room > len(chunk) {
+				room = len(chunk)
+			}
+
+			line = append(line, chunk[:room]...)
+		}
+
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+
+		return bytes.TrimRight(line, "\r\n"), err
+	}
+}
+
+// contextLine returns line for display, which is quoted if it's not valid UTF-8.
+func contextLine(line []byte) string {
+	if !utf8.Valid(line) {
+		return fmt.Sprintf("%q", line)
+	}
+
+	return string(line)
+}
+
+// streamMismatch represents the first difference of two streams.
+type streamMismatch struct {
+	offset   int64
+	line     int
+	column   int
+	expected string
+	actual   string
+}
+
+// compareStreams reads expected and actual by chunks, and returns their first difference with
+// lines of context around, or nil if they're equal. It's stopped once ctx is done.
+func compareStreams(ctx context.Context, expected, actual io.Reader, lines int) (*streamMismatch, error) {
+	pos := &streamPosition{
+		line:    1,
+		context: lines,
+	}
+
+	expbuf := make([]byte, readerChunkSize)
+	actbuf := make([]byte, readerChunkSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		expn, err := io.ReadFull(expected, expbuf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return nil, err
+		}
+
+		actn, err := io.ReadFull(actual, actbuf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return nil, err
+		}
+
+		n := expn
+		if actn < n {
+			n = actn
+		}
+
+		i := 0
+		for i < n && expbuf[i] == actbuf[i] {
+			i++
+		}
+
+		if i == n && expn == actn {
+			pos.advance(expbuf[:n])
+
+			// both streams are drained
+			if n < readerChunkSize {
+				return nil, nil
+			}
+
+			continue
+		}
+
+		pos.advance(expbuf[:i])
+
+		return &streamMismatch{
+			offset:   pos.offset,
+			line:     pos.line,
+			column:   pos.column + 1,
+			expected: pos.excerpt(io.MultiReader(bytes.NewReader(expbuf[i:expn]), expected)),
+			actual:   pos.excerpt(io.MultiReader(bytes.NewReader(actbuf[i:actn]), actual)),
+		}, nil
+	}
+}
+
+// assertEqualStreams compares expected and actual by streaming, with labels describing them.
+func assertEqualStreams(t TestingT, expected, actual io.Reader, labels []labeledOutput, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	mismatch, err := compareStreams(contextOf(t), expected, actual, configOf(t).DiffContext)
+	if err != nil {
+		return Errorf(t, fmt.Sprintf("Expect to read io.Reader, but failed: %v", err), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+		}, labels)
+	}
+
+	if mismatch != nil {
+		return Errorf(t, fmt.Sprintf("Expect to be equal, but differ at byte %d (line %d, column %d)", mismatch.offset, mismatch.line, mismatch.column), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+		}, labels, []labeledOutput{
+			{
+				label:   "-expected",
+				content: mismatch.expected,
+			},
+			{
+				label:   "+received",
+				content: mismatch.actual,
+			},
+		})
+	}
+
+	return true
+}
+
+// EqualReaders asserts that the two readers have the same content. They're compared by chunks
+// and stopped at the first difference, so it works with huge inputs.
+//
+//	assert.EqualReaders(t, expectedReader, resp.Body)
+//
+// Returns whether the assertion was successful (true) or not (false).
+func EqualReaders(t TestingT, expected, actual io.Reader, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	return assertEqualStreams(t, expected, actual, nil, extras...)
+}
+
+// EqualFiles asserts that the two files have the same content. They're compared by chunks
+// and stopped at the first difference, so it works with huge files.
+//
+//	assert.EqualFiles(t, "testdata/golden.csv", "output/result.csv")
+//
+// Returns whether the assertion was successful (true) or not (false).
+func EqualFiles(t TestingT, expected, actual string, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	labels := []labeledOutput{
+		{
+			label:   "-file",
+			content: expected,
+		},
+		{
+			label:   "+file",
+			content: actual,
+		},
+	}
+
+	expfile, err := os.Open(expected)
+	if err != nil {
+		return Errorf(t, fmt.Sprintf("Expect to open file, but failed: %v", err), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+		}, labels, arguments{
+			"expected": expected,
+			"actual":   actual,
+		})
+	}
+	defer expfile.Close()
+
+	actfile, err := os.Open(actual)
+	if err != nil {
+		return Errorf(t, fmt.Sprintf("Expect to open file, but failed: %v", err), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+		}, labels, arguments{
+			"expected": expected,
+			"actual":   actual,
+		})
+	}
+	defer actfile.Close()
+
+	return assertEqualStreams(t, expfile, actfile, labels, extras...)
+}
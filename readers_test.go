@@ -0,0 +1,231 @@
+package gospec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_searchReader(t *testing.T) {
+	// needle across boundary of chunks
+	data := strings.Repeat("a", readerChunkSize-2) + "needle" + strings.Repeat("b", 10)
+
+	found, n, err := searchReader(&testingReader{s: data}, []byte("needle"), nil)
+	True(t, found)
+	Equal(t, int64(len(data)), n)
+	Nil(t, err)
+
+	found, n, err = searchReader(&testingReader{s: data}, []byte("missing"), nil)
+	False(t, found)
+	Equal(t, int64(len(data)), n)
+	Nil(t, err)
+
+	found, _, _ = searchReader(&testingReader{s: data}, nil, nil)
+	True(t, found)
+}
+
+func TestContainsElementForReader(t *testing.T) {
+	// io.Seeker is rewound to its current offset rather than the start
+	rs := strings.NewReader("Hello, world!")
+	rs.Seek(7, io.SeekStart)
+
+	True(t, ContainsElement(rs, []byte("world")))
+	False(t, ContainsElement(rs, "Hello"))
+	Equal(t, 6, rs.Len())
+
+	// *bytes.Buffer is searched without reading
+	buf := bytes.NewBufferString("Hello, world!")
+	True(t, ContainsElement(buf, "world"))
+	Equal(t, 13, buf.Len())
+}
+
+func TestContainsForUnseekableReader(t *testing.T) {
+	rt := new(RecordingT)
+
+	// the reader is never consumed
+	r := &testingReader{s: "Hello, world!"}
+
+	False(t, Contains(rt, r, "Hello"))
+	False(t, NotContains(rt, r, "Salut"))
+	False(t, ContainsAny(rt, r, []string{"Salut", "Hello"}))
+	False(t, ContainsAll(rt, r, []string{"world", "Hello"}))
+	Equal(t, int64(0), r.i)
+
+	records := rt.Records()
+	if Len(t, records, 4) {
+		for _, record := range records {
+			Equal(t, "Parameter must be an io.Seeker or expose Bytes() to be searched without consuming it, but got *gospec.testingReader, use ReaderContains instead", record.Error)
+		}
+	}
+}
+
+func TestContainsForReaderError(t *testing.T) {
+	rt := new(RecordingT)
+
+	r := io.NewSectionReader(testingReaderAt{}, 0, 10)
+
+	False(t, Contains(rt, r, "Hello"))
+	False(t, ContainsAll(rt, r, []string{"Hello"}))
+
+	records := rt.Records()
+	if Len(t, records, 2) {
+		Equal(t, "Expect to read io.Reader, but failed: broken", records[0].Error)
+		Equal(t, "Expect to read io.Reader, but failed: broken", records[1].Error)
+	}
+}
+
+func TestContainsAllForReader(t *testing.T) {
+	mockT := new(testing.T)
+
+	// searched once for all elements, and rewound to its current offset
+	data := strings.Repeat("a", readerChunkSize-2) + "Hello" + strings.Repeat("b", readerChunkSize) + "world"
+
+	rs := strings.NewReader(data)
+	rs.Seek(1, io.SeekStart)
+
+	True(t, ContainsAll(mockT, rs, []string{"world", "Hello"}))
+	False(t, ContainsAll(mockT, rs, []string{"world", "Salut"}))
+	True(t, ContainsAny(mockT, rs, []string{"Salut", "world"}))
+	Equal(t, len(data)-1, rs.Len())
+}
+
+func Test_searchReaderAll(t *testing.T) {
+	// needles across boundary of chunks
+	data := strings.Repeat("a", readerChunkSize-2) + "needle" + strings.Repeat("b", readerChunkSize-1) + "pin"
+
+	found, n, err := searchReaderAll(&testingReader{s: data}, [][]byte{[]byte("pin"), []byte("needle"), []byte("missing"), nil})
+	Equal(t, []bool{true, true, false, true}, found)
+	Equal(t, int64(len(data)), n)
+	Nil(t, err)
+
+	// stopped once all needles found
+	found, n, err = searchReaderAll(&testingReader{s: data}, [][]byte{[]byte("needle")})
+	Equal(t, []bool{true}, found)
+	Equal(t, int64(2*readerChunkSize), n)
+	Nil(t, err)
+
+	found, n, _ = searchReaderAll(&testingReader{s: data}, [][]byte{nil})
+	Equal(t, []bool{true}, found)
+	Equal(t, int64(0), n)
+}
+
+type testingReaderAt struct{}
+
+func (testingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	return 0, errors.New("broken")
+}
+
+func TestReaderContains(t *testing.T) {
+	mockT := new(testing.T)
+
+	r := &testingReader{s: "Hello, world!"}
+
+	replay, ok := ReaderContains(mockT, r, "Hello")
+	True(t, ok)
+
+	data, err := io.ReadAll(replay)
+	Nil(t, err)
+	Equal(t, "Hello, world!", string(data))
+	Nil(t, replay.Close())
+
+	replay, ok = ReaderContains(mockT, &testingReader{s: "Hello, world!"}, []byte("Salut"))
+	False(t, ok)
+
+	data, _ = io.ReadAll(replay)
+	Equal(t, "Hello, world!", string(data))
+}
+
+func TestReaderContainsWithSpool(t *testing.T) {
+	mockT := new(testing.T)
+
+	data := strings.Repeat("0123456789", readerSpoolSize/5) + "needle" + "tail"
+
+	replay, ok := ReaderContains(mockT, &testingReader{s: data}, "needle")
+	True(t, ok)
+	NotNil(t, replay.(*replayReader).spool.file)
+
+	received, err := io.ReadAll(replay)
+	Nil(t, err)
+	True(t, string(received) == data, "replayed data should be equal to the origin")
+	Nil(t, replay.Close())
+}
+
+func Test_compareStreams(t *testing.T) {
+	expected := "line 1\nline 2\nline 3\nline 4\nline 5\n"
+	actual := "line 1\nline 2\nline X\nline 4\nline 5\n"
+
+	mismatch, err := compareStreams(context.Background(), strings.NewReader(expected), strings.NewReader(actual), 1)
+	Nil(t, err)
+	Equal(t, int64(19), mismatch.offset)
+	Equal(t, 3, mismatch.line)
+	Equal(t, 6, mismatch.column)
+	Equal(t, "     2 | line 2\n     3 | line 3\n     4 | line 4", mismatch.expected)
+	Equal(t, "     2 | line 2\n     3 | line X\n     4 | line 4", mismatch.actual)
+
+	mismatch, err = compareStreams(context.Background(), strings.NewReader(expected), strings.NewReader(expected[:10]), 0)
+	Nil(t, err)
+	Equal(t, int64(10), mismatch.offset)
+	Equal(t, "     2 | line 2", mismatch.expected)
+	Equal(t, "     2 | lin\n       | <EOF>", mismatch.actual)
+
+	// difference beyond the first chunk
+	long := strings.Repeat("0123456789\n", readerChunkSize/5)
+
+	mismatch, err = compareStreams(context.Background(), strings.NewReader(long+"a"), strings.NewReader(long+"b"), 1)
+	Nil(t, err)
+	Equal(t, int64(len(long)), mismatch.offset)
+	Equal(t, readerChunkSize/5+1, mismatch.line)
+	Equal(t, 1, mismatch.column)
+
+	mismatch, err = compareStreams(context.Background(), strings.NewReader(long), &testingReader{s: long}, 1)
+	Nil(t, err)
+	Nil(t, mismatch)
+
+	// canceled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	mismatch, err = compareStreams(ctx, strings.NewReader(long), strings.NewReader(long), 1)
+	Equal(t, context.Canceled, err)
+	Nil(t, mismatch)
+}
+
+func TestEqualReaders(t *testing.T) {
+	mockT := new(testing.T)
+
+	True(t, EqualReaders(mockT, strings.NewReader(""), strings.NewReader("")))
+	True(t, EqualReaders(mockT, strings.NewReader("Hello"), bytes.NewBufferString("Hello")))
+	False(t, EqualReaders(mockT, strings.NewReader("Hello"), strings.NewReader("Hallo")))
+	False(t, EqualReaders(mockT, strings.NewReader("Hello"), strings.NewReader("Hello!")))
+}
+
+func TestEqualFiles(t *testing.T) {
+	mockT := new(testing.T)
+
+	dir, err := os.MkdirTemp("", "gospec")
+	Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	expected := filepath.Join(dir, "expected.csv")
+	actual := filepath.Join(dir, "actual.csv")
+	os.WriteFile(expected, []byte("id,name\n1,foo\n2,bar\n"), 0644)
+	os.WriteFile(actual, []byte("id,name\n1,foo\n2,baz\n"), 0644)
+
+	True(t, EqualFiles(mockT, expected, expected))
+	False(t, EqualFiles(mockT, expected, filepath.Join(dir, "missing.csv")))
+
+	rt := new(RecordingT)
+
+	False(t, EqualFiles(rt, expected, actual))
+
+	records := rt.Records()
+	if Len(t, records, 1) {
+		Contains(t, records[0].Error, "differ at byte 18 (line 3, column 5)")
+		Equal(t, "     2 | 1,foo\n     3 | 2,bar\n       | <EOF>", records[0].Expected)
+	}
+}
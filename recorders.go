@@ -0,0 +1,234 @@
+package gospec
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// RecordingT is a TestingT recording failures of assertions instead of failing the test,
+// which is useful for testing custom assertions and their output.
+//
+//	rt := gospec.NewRecordingT("TestCustom")
+//	AssertPositive(rt, -1)
+//
+//	gospec.True(t, rt.Failed())
+//	gospec.Equal(t, "Expect to be positive", rt.Records()[0].Error)
+//
+// RecordingT implements Reporter, so failures reported by Errorf with it are recorded as
+// FailureRecord without registering it by AddReporter. They're not dispatched to reporters
+// registered, since they're not failures of the test.
+//
+// NOTE: FailNow stops the goroutine calling it by runtime.Goexit like *testing.T does,
+// so assertions stopping the test, e.g. with Require, should be run by Run.
+type RecordingT struct {
+	mux     sync.Mutex
+	name    string
+	outputs []string
+	records []*FailureRecord
+	failed  bool
+	stopped bool
+}
+
+// NewRecordingT returns a RecordingT named name, which names records of its failures.
+func NewRecordingT(name string) *RecordingT {
+	return &RecordingT{
+		name: name,
+	}
+}
+
+// Errorf implements TestingT.
+func (rt *RecordingT) Errorf(format string, args ...interface{}) {
+	rt.mux.Lock()
+	defer rt.mux.Unlock()
+
+	rt.outputs = append(rt.outputs, fmt.Sprintf(format, args...))
+	rt.failed = true
+}
+
+// Helper implements HelperT.
+func (rt *RecordingT) Helper() {}
+
+// Name implements NameT.
+func (rt *RecordingT) Name() string {
+	return rt.name
+}
+
+// FailNow implements FailNowT. It marks rt as failed and stopped, and stops the calling goroutine.
+func (rt *RecordingT) FailNow() {
+	rt.mux.Lock()
+	rt.failed = true
+	rt.stopped = true
+	rt.mux.Unlock()
+
+	runtime.Goexit()
+}
+
+// Report implements Reporter.
+func (rt *RecordingT) Report(t TestingT, record *FailureRecord) {
+	rt.mux.Lock()
+	defer rt.mux.Unlock()
+
+	rt.records = append(rt.records, record)
+}
+
+// Run calls fn with rt in a new goroutine and waits for it, so FailNow stops fn only.
+// A panic of fn is recorded as a failure of rt rather than crashing the test binary.
+// Returns whether fn completed without failures (true) or not (false).
+func (rt *RecordingT) Run(fn func(t TestingT)) bool {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		defer func() {
+			if r := recover(); r != nil {
+				Errorf(rt, "Expect to NOT panic with invocation", []labeledOutput{
+					{
+						label:   "Panic Value",
+						content: fmt.Sprintf("%v", r),
+					},
+					{
+						label:   "Panic Stack",
+						content: string(debug.Stack()),
+					},
+				})
+			}
+		}()
+
+		fn(rt)
+	}()
+
+	<-done
+
+	return !rt.Failed()
+}
+
+// Failed returns whether any failure is recorded.
+func (rt *RecordingT) Failed() bool {
+	rt.mux.Lock()
+	defer rt.mux.Unlock()
+
+	return rt.failed
+}
+
+// Stopped returns whether FailNow is called.
+func (rt *RecordingT) Stopped() bool {
+	rt.mux.Lock()
+	defer rt.mux.Unlock()
+
+	return rt.stopped
+}
+
+// Records returns failures recorded in order.
+func (rt *RecordingT) Records() []*FailureRecord {
+	rt.mux.Lock()
+	defer rt.mux.Unlock()
+
+	return append([]*FailureRecord{}, rt.records...)
+}
+
+// Outputs returns the text output of failures recorded in order.
+func (rt *RecordingT) Outputs() []string {
+	rt.mux.Lock()
+	defer rt.mux.Unlock()
+
+	return append([]string{}, rt.outputs...)
+}
+
+// String returns the text output of all failures recorded.
+func (rt *RecordingT) String() string {
+	return strings.Join(rt.Outputs(), "\n")
+}
+
+// Reset drops all failures recorded.
+func (rt *RecordingT) Reset() {
+	rt.mux.Lock()
+	defer rt.mux.Unlock()
+
+	rt.outputs = nil
+	rt.records = nil
+	rt.failed = false
+	rt.stopped = false
+}
+
+// recordFailures calls fn with a RecordingT using the config of t.
+func recordFailures(t TestingT, fn func(rt TestingT)) *RecordingT {
+	rt := NewRecordingT(nameOf(t))
+	rt.Run(func(_ TestingT) {
+		fn(WithConfig(rt, func(c *Config) {
+			*c = configOf(t).clone()
+		}))
+	})
+
+	return rt
+}
+
+// ExpectFailure asserts that fn fails with the TestingT given, and the output of failures contains substr.
+// The TestingT given records failures instead of failing t.
+//
+//	gospec.ExpectFailure(t, func(rt gospec.TestingT) {
+//		AssertPositive(rt, -1)
+//	}, "Expect to be positive")
+//
+// Returns whether the assertion was successful (true) or not (false).
+func ExpectFailure(t TestingT, fn func(rt TestingT), substr string, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	rt := recordFailures(t, fn)
+	if !rt.Failed() {
+		return Errorf(t, "Expect to fail, but succeeded", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+		}, arguments{
+			"substr": substr,
+		})
+	}
+
+	if !strings.Contains(rt.String(), substr) {
+		return Errorf(t, fmt.Sprintf("Expect failures to contain %#v, but not", substr), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "+received",
+				content: rt.String(),
+			},
+		}, arguments{
+			"substr": substr,
+		})
+	}
+
+	return true
+}
+
+// ExpectNoFailure asserts that fn succeeds with the TestingT given, which records failures instead of failing t.
+//
+// Returns whether the assertion was successful (true) or not (false).
+func ExpectNoFailure(t TestingT, fn func(rt TestingT), extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	rt := recordFailures(t, fn)
+	if rt.Failed() {
+		return Errorf(t, fmt.Sprintf("Expect to succeed, but failed %d times", len(rt.Outputs())), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "+received",
+				content: rt.String(),
+			},
+		})
+	}
+
+	return true
+}
@@ -0,0 +1,160 @@
+package gospec
+
+import (
+	"strings"
+	"testing"
+)
+
+// testingLabelOf returns the content of the label of record.
+func testingLabelOf(record *FailureRecord, label string) string {
+	for _, l := range record.Labels {
+		if l.Label == label {
+			return l.Content
+		}
+	}
+
+	return ""
+}
+
+func TestRecordingT(t *testing.T) {
+	rt := NewRecordingT("TestRecording")
+
+	True(t, Equal(rt, 1, 1))
+	False(t, rt.Failed())
+	Equal(t, 0, len(rt.Records()))
+	Equal(t, "", rt.String())
+
+	False(t, Equal(rt, "want", "got", "Hello, %s", "world!"))
+	True(t, rt.Failed())
+	False(t, rt.Stopped())
+	Equal(t, 1, len(rt.Outputs()))
+	Contains(t, rt.String(), "Expect to be equal")
+
+	records := rt.Records()
+	if Equal(t, 1, len(records)) {
+		Equal(t, "TestRecording", records[0].Test)
+		Equal(t, "Equal", records[0].Assertion)
+		Equal(t, "Expect to be equal", records[0].Error)
+		Equal(t, "Hello, world!", records[0].Message)
+		Contains(t, records[0].Diff, `+(string) (len=3) "got"`)
+	}
+
+	// records are not duplicated if it's registered
+	AddReporter(rt)
+	defer RemoveReporter(rt)
+
+	False(t, Equal(rt, 1, 2))
+	Equal(t, 2, len(rt.Records()))
+
+	rt.Reset()
+	False(t, rt.Failed())
+	Equal(t, 0, len(rt.Records()))
+	Equal(t, 0, len(rt.Outputs()))
+}
+
+func TestRecordingT_Run(t *testing.T) {
+	rt := new(RecordingT)
+
+	True(t, rt.Run(func(rt TestingT) {
+		Equal(rt, 1, 1)
+	}))
+
+	reached := false
+	False(t, rt.Run(func(rt TestingT) {
+		requireT := Require(rt)
+
+		Equal(requireT, 1, 2)
+		reached = true
+	}))
+	True(t, rt.Stopped())
+	False(t, reached)
+	Equal(t, 1, len(rt.Records()))
+
+	// panics are recorded
+	rt.Reset()
+
+	False(t, rt.Run(func(rt TestingT) {
+		panic("boom")
+	}))
+
+	records := rt.Records()
+	if Len(t, records, 1) {
+		Equal(t, "Expect to NOT panic with invocation", records[0].Error)
+		Equal(t, FailureLabel{Label: "Panic Value", Content: "boom"}, records[0].Labels[0])
+
+		// traces start with the panicking func, without frames of runtime
+		if True(t, len(records[0].Frames) > 0) {
+			Equal(t, "recorders_test.go", records[0].Frames[0].File)
+		}
+		for _, frame := range records[0].Frames {
+			False(t, strings.HasPrefix(frame.Function, "runtime."), "Unexpected frame %v", frame)
+		}
+	}
+}
+
+func TestRecordingT_Reporters(t *testing.T) {
+	reporter := new(RecordingT)
+
+	AddReporter(reporter)
+	defer RemoveReporter(reporter)
+
+	// failures recorded are not reported
+	True(t, ExpectFailure(new(testing.T), func(rt TestingT) {
+		testingAssertPositive(rt, -1)
+	}, "Expect to be true"))
+	False(t, ExpectNoFailure(new(RecordingT), func(rt TestingT) {
+		testingAssertPositive(rt, -1)
+	}))
+	Equal(t, 0, len(reporter.Records()))
+
+	False(t, Equal(new(testing.T), 1, 2))
+	Equal(t, 1, len(reporter.Records()))
+}
+
+func TestExpectFailure(t *testing.T) {
+	mockT := new(testing.T)
+
+	True(t, ExpectFailure(mockT, func(rt TestingT) {
+		testingAssertPositive(rt, -1)
+	}, "Expect to be true"))
+	False(t, mockT.Failed())
+
+	// stopped by FailNow
+	True(t, ExpectFailure(mockT, func(rt TestingT) {
+		testingAssertPositive(Require(rt), -1)
+		testingAssertPositive(rt, 1)
+	}, "Expect to be true"))
+	False(t, mockT.Failed())
+
+	rt := new(RecordingT)
+
+	False(t, ExpectFailure(rt, func(rt TestingT) {
+		testingAssertPositive(rt, 1)
+	}, "Expect to be true"))
+	Contains(t, rt.String(), "Expect to fail, but succeeded")
+
+	rt.Reset()
+
+	False(t, ExpectFailure(rt, func(rt TestingT) {
+		testingAssertPositive(rt, -1)
+	}, "Should be positive"))
+	Contains(t, rt.String(), `Expect failures to contain "Should be positive", but not`)
+	Contains(t, rt.String(), "Expect to be true")
+}
+
+func TestExpectNoFailure(t *testing.T) {
+	mockT := new(testing.T)
+
+	True(t, ExpectNoFailure(mockT, func(rt TestingT) {
+		testingAssertPositive(rt, 1)
+	}))
+	False(t, mockT.Failed())
+
+	rt := new(RecordingT)
+
+	False(t, ExpectNoFailure(rt, func(rt TestingT) {
+		testingAssertPositive(rt, -1)
+		testingAssertPositive(rt, -2)
+	}))
+	Contains(t, rt.String(), "Expect to succeed, but failed 2 times")
+}
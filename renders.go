@@ -0,0 +1,329 @@
+package gospec
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	ansiReset     = "\x1b[0m"
+	ansiBold      = "\x1b[1m"
+	ansiRed       = "\x1b[31m"
+	ansiGreen     = "\x1b[32m"
+	ansiCyan      = "\x1b[36m"
+	ansiInverse   = "\x1b[7m"
+	ansiNoInverse = "\x1b[27m"
+)
+
+// ColorMode defines when failure output is colorized with ANSI escape codes.
+type ColorMode int
+
+// Color modes
+const (
+	// ColorAuto colorizes output unless NO_COLOR is set, TERM is dumb or os.Stdout is not a terminal.
+	ColorAuto ColorMode = iota
+	ColorAlways
+	ColorNever
+)
+
+// DiffStyle defines the layout of diffs in failure output.
+type DiffStyle int
+
+// Diff styles
+const (
+	DiffUnified DiffStyle = iota
+	DiffSideBySide
+)
+
+// SetColorMode changes when failure output is colorized.
+func SetColorMode(mode ColorMode) {
+	UpdateConfig(func(c *Config) {
+		c.Color = mode
+	})
+}
+
+// SetDiffStyle changes the layout of diffs in failure output.
+func SetDiffStyle(style DiffStyle) {
+	UpdateConfig(func(c *Config) {
+		c.DiffStyle = style
+	})
+}
+
+// SetDiffContext changes the number of context lines around changes of diffs.
+func SetDiffContext(lines int) {
+	if lines < 0 {
+		lines = 0
+	}
+
+	UpdateConfig(func(c *Config) {
+		c.DiffContext = lines
+	})
+}
+
+// colorEnabled returns true if failure output should be colorized.
+func (c *Config) colorEnabled() bool {
+	switch c.Color {
+	case ColorAlways:
+		return true
+
+	case ColorNever:
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// getTerminalWidth returns columns of the terminal which os.Stdout, the output of tests, is written to.
+// It falls back to $COLUMNS if os.Stdout is not a terminal, e.g. piped by `go test`, or 120 by default.
+func getTerminalWidth() int {
+	if columns, ok := terminalWidth(os.Stdout.Fd()); ok {
+		return columns
+	}
+
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+
+	return 120
+}
+
+// renderDiff renders a unified diff produced by diff() with the style and colors of config.
+func renderDiff(unified string, c *Config) string {
+	if unified == "" {
+		return unified
+	}
+
+	color := c.colorEnabled()
+
+	if c.DiffStyle == DiffSideBySide && strings.Contains(unified, "\n@@ ") {
+		return renderSideBySide(unified, getTerminalWidth(), color)
+	}
+
+	if !color {
+		return unified
+	}
+
+	return renderUnified(unified)
+}
+
+// renderUnified colorizes a unified diff, highlighting changed characters of paired lines.
+func renderUnified(unified string) string {
+	lines := strings.Split(unified, "\n")
+
+	inHunk := false
+
+	buf := new(strings.Builder)
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case !inHunk && strings.HasPrefix(line, "---"):
+			buf.WriteString(ansiBold + ansiRed + line + ansiReset)
+
+		case !inHunk && strings.HasPrefix(line, "+++"):
+			buf.WriteString(ansiBold + ansiGreen + line + ansiReset)
+
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+
+			buf.WriteString(ansiCyan + line + ansiReset)
+
+		case inHunk && (strings.HasPrefix(line, "-") || strings.HasPrefix(line, "+")):
+			removed, added, next := collectChanges(lines, i)
+
+			for j, s := range removed {
+				if j < len(added) {
+					s, _ = highlightChanges(s, added[j], ansiRed)
+				} else {
+					s = ansiRed + s + ansiReset
+				}
+
+				buf.WriteString("-" + s + "\n")
+			}
+
+			for j, s := range added {
+				if j < len(removed) {
+					_, s = highlightChanges(removed[j], s, ansiGreen)
+				} else {
+					s = ansiGreen + s + ansiReset
+				}
+
+				buf.WriteString("+" + s + "\n")
+			}
+
+			i = next
+			continue
+
+		default:
+			buf.WriteString(line)
+
+		}
+
+		if i < len(lines)-1 {
+			buf.WriteString("\n")
+		}
+
+		i++
+	}
+
+	return buf.String()
+}
+
+// renderSideBySide renders hunks of a unified diff in two columns, expected on the left
+// and actual on the right, which fits into the width.
+func renderSideBySide(unified string, width int, color bool) string {
+	lines := strings.Split(strings.TrimRight(unified, "\n"), "\n")
+
+	column := (width - 3) / 2
+	if column < 10 {
+		column = 10
+	}
+
+	buf := new(strings.Builder)
+	writeRow := func(left, right string, sep string, leftColor, rightColor string) {
+		left = fitColumn(left, column)
+		right = fitColumn(right, column)
+
+		if color {
+			if leftColor != "" {
+				left = leftColor + left + ansiReset
+			}
+			if rightColor != "" {
+				right = rightColor + right + ansiReset
+			}
+		}
+
+		buf.WriteString(strings.TrimRight(left+" "+sep+" "+right, " ") + "\n")
+	}
+
+	inHunk := false
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case !inHunk && strings.HasPrefix(line, "---"):
+			title := strings.TrimSpace(strings.TrimPrefix(line, "---"))
+			if i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++") {
+				writeRow(title, strings.TrimSpace(strings.TrimPrefix(lines[i+1], "+++")), "|", ansiBold+ansiRed, ansiBold+ansiGreen)
+
+				i += 2
+				continue
+			}
+
+			writeRow(title, "", "|", ansiBold+ansiRed, "")
+
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+
+			writeRow(line, line, "|", ansiCyan, ansiCyan)
+
+		case inHunk && (strings.HasPrefix(line, "-") || strings.HasPrefix(line, "+")):
+			removed, added, next := collectChanges(lines, i)
+
+			rows := len(removed)
+			if len(added) > rows {
+				rows = len(added)
+			}
+
+			for j := 0; j < rows; j++ {
+				switch {
+				case j < len(removed) && j < len(added):
+					writeRow(removed[j], added[j], "|", ansiRed, ansiGreen)
+
+				case j < len(removed):
+					writeRow(removed[j], "", "<", ansiRed, "")
+
+				default:
+					writeRow("", added[j], ">", "", ansiGreen)
+
+				}
+			}
+
+			i = next
+			continue
+
+		default:
+			content := strings.TrimPrefix(line, " ")
+
+			writeRow(content, content, " ", "", "")
+
+		}
+
+		i++
+	}
+
+	return buf.String()
+}
+
+// collectChanges returns the run of removed and added lines starting at lines[i],
+// without the leading "-" and "+", and the index of the next line.
+func collectChanges(lines []string, i int) (removed, added []string, next int) {
+	for ; i < len(lines) && strings.HasPrefix(lines[i], "-"); i++ {
+		removed = append(removed, lines[i][1:])
+	}
+
+	for ; i < len(lines) && strings.HasPrefix(lines[i], "+"); i++ {
+		added = append(added, lines[i][1:])
+	}
+
+	return removed, added, i
+}
+
+// highlightChanges colorizes both strings with color, and marks the characters differing between
+// them in reverse video. The common prefix and suffix are not marked.
+func highlightChanges(removed, added, color string) (string, string) {
+	prefix := 0
+	for prefix < len(removed) && prefix < len(added) && removed[prefix] == added[prefix] {
+		prefix++
+	}
+	for prefix > 0 &&
+		((prefix < len(removed) && !utf8.RuneStart(removed[prefix])) || (prefix < len(added) && !utf8.RuneStart(added[prefix]))) {
+		prefix--
+	}
+
+	suffix := 0
+	for suffix < len(removed)-prefix && suffix < len(added)-prefix &&
+		removed[len(removed)-1-suffix] == added[len(added)-1-suffix] {
+		suffix++
+	}
+	for suffix > 0 && !utf8.RuneStart(removed[len(removed)-suffix]) {
+		suffix--
+	}
+
+	mark := func(s string) string {
+		middle := s[prefix : len(s)-suffix]
+		if middle == "" {
+			return color + s + ansiReset
+		}
+
+		return color + s[:prefix] + ansiInverse + middle + ansiNoInverse + s[len(s)-suffix:] + ansiReset
+	}
+
+	return mark(removed), mark(added)
+}
+
+// fitColumn pads or truncates s to width runes, expanding tabs as spaces.
+func fitColumn(s string, width int) string {
+	s = strings.Replace(s, "\t", "    ", -1)
+
+	n := utf8.RuneCountInString(s)
+	if n > width {
+		runes := []rune(s)
+
+		return string(runes[:width-1]) + "…"
+	}
+
+	return s + strings.Repeat(" ", width-n)
+}
@@ -0,0 +1,101 @@
+package gospec
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func Test_renderUnified(t *testing.T) {
+	unified := diff(
+		struct{ foo string }{"hello"},
+		struct{ foo string }{"hallo"},
+	)
+
+	expected := ansiBold + ansiRed + "--- Expected" + ansiReset + "\n" +
+		ansiBold + ansiGreen + "+++ Actual" + ansiReset + "\n" +
+		ansiCyan + "@@ -1,3 +1,3 @@" + ansiReset + "\n" +
+		" (struct { foo string }) {\n" +
+		"-" + ansiRed + ` foo: (string) (len=5) "h` + ansiInverse + "e" + ansiNoInverse + `llo"` + ansiReset + "\n" +
+		"+" + ansiGreen + ` foo: (string) (len=5) "h` + ansiInverse + "a" + ansiNoInverse + `llo"` + ansiReset + "\n" +
+		" }\n"
+	Equal(t, expected, renderUnified(unified))
+
+	Equal(t, ansiBold+ansiRed+"--- int(1)"+ansiReset+"\n"+ansiBold+ansiGreen+"+++ int(2)"+ansiReset+"\n\n", renderUnified(diff(1, 2)))
+}
+
+func Test_renderSideBySide(t *testing.T) {
+	unified := diff(
+		[]int{1, 2, 3, 4},
+		[]int{1, 3, 5, 7},
+	)
+
+	expected := `Expected              | Actual
+@@ -2,5 +2,5 @@       | @@ -2,5 +2,5 @@
+ (int) 1,                (int) 1,
+ (int) 2,             <
+ (int) 3,                (int) 3,
+ (int) 4              |  (int) 5,
+                      >  (int) 7
+}                       }
+`
+	Equal(t, expected, renderSideBySide(unified, 45, false))
+}
+
+func Test_highlightChanges(t *testing.T) {
+	removed, added := highlightChanges("héllo wörld", "héllo wörd", "")
+	Equal(t, "héllo wör"+ansiInverse+"l"+ansiNoInverse+"d"+ansiReset, removed)
+	Equal(t, "héllo wörd"+ansiReset, added)
+
+	removed, added = highlightChanges("ä", "ö", "")
+	Equal(t, ansiInverse+"ä"+ansiNoInverse+ansiReset, removed)
+	Equal(t, ansiInverse+"ö"+ansiNoInverse+ansiReset, added)
+}
+
+func Test_renderDiffWithNoColor(t *testing.T) {
+	os.Setenv("NO_COLOR", "1")
+	defer os.Unsetenv("NO_COLOR")
+
+	unified := diff("Hello", "World")
+
+	c := DefaultConfig()
+	False(t, c.colorEnabled())
+	Equal(t, unified, renderDiff(unified, &c))
+
+	c.Color = ColorAlways
+	True(t, c.colorEnabled())
+	True(t, strings.Contains(renderDiff(unified, &c), ansiRed))
+}
+
+func Test_getTerminalWidth(t *testing.T) {
+	file, err := os.CreateTemp("", "gospec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	// files are not terminals
+	_, ok := terminalWidth(file.Fd())
+	False(t, ok)
+
+	if columns, ok := terminalWidth(os.Stdout.Fd()); ok {
+		Equal(t, columns, getTerminalWidth())
+		return
+	}
+
+	os.Setenv("COLUMNS", "80")
+	defer os.Unsetenv("COLUMNS")
+
+	Equal(t, 80, getTerminalWidth())
+
+	os.Setenv("COLUMNS", "invalid")
+	Equal(t, 120, getTerminalWidth())
+}
+
+func TestSetDiffContext(t *testing.T) {
+	SetDiffContext(0)
+	defer SetDiffContext(1)
+
+	Equal(t, "--- Expected\n+++ Actual\n@@ -3 +3 @@\n- (int) 2,\n+ (int) 5,\n", diff([]int{1, 2, 3}, []int{1, 5, 3}))
+}
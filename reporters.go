@@ -0,0 +1,310 @@
+package gospec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// JSONReportPrefix is the envelope prefix of failure records logged by JSONReporter
+	// through t.Log, which makes it easy for tools to pick them out of `go test` output.
+	JSONReportPrefix = "@gospec:json "
+
+	// envJSONReport enables a default JSONReporter. Its value is either a file path which
+	// records are appended to, or "log" for logging records through t.Log.
+	envJSONReport = "GOSPEC_JSON_REPORT"
+
+	// envGitHubAnnotations enables a default GitHubReporter writing to os.Stdout if it's "1" or "true".
+	envGitHubAnnotations = "GOSPEC_GITHUB_ANNOTATIONS"
+)
+
+// FailureLabel represents a labeled content of failure output.
+type FailureLabel struct {
+	Label   string `json:"label"`
+	Content string `json:"content"`
+}
+
+// FailureRecord is the structured form of a failure reported by Errorf.
+type FailureRecord struct {
+	Time      time.Time      `json:"time"`
+	Test      string         `json:"test,omitempty"`
+	Assertion string         `json:"assertion,omitempty"`
+	Error     string         `json:"error"`
+	Message   string         `json:"message,omitempty"`
+	Expected  string         `json:"expected,omitempty"`
+	Actual    string         `json:"actual,omitempty"`
+	Diff      string         `json:"diff,omitempty"`
+	Labels    []FailureLabel `json:"labels,omitempty"`
+	Trace     []string       `json:"trace,omitempty"`
+	Frames    []TraceFrame   `json:"frames,omitempty"`
+}
+
+// Reporter defines an extra destination of failures reported by Errorf.
+type Reporter interface {
+	Report(t TestingT, record *FailureRecord)
+}
+
+var (
+	reportersMux sync.RWMutex
+	reporters    []Reporter
+)
+
+func init() {
+	switch strings.ToLower(os.Getenv(envGitHubAnnotations)) {
+	case "1", "true":
+		AddReporter(NewGitHubReporter(nil))
+	}
+
+	switch target := os.Getenv(envJSONReport); target {
+	case "":
+		// ignore
+
+	case "log":
+		AddReporter(NewJSONLogReporter())
+
+	default:
+		reporter, err := NewJSONFileReporter(target)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[WARN] gospec: cannot open %s=%s: %v\n", envJSONReport, target, err)
+			return
+		}
+
+		AddReporter(reporter)
+	}
+}
+
+// AddReporter registers a reporter which receives every failure reported by Errorf.
+//
+// NOTE: use a reporter of comparable type, such as a pointer, if it needs to be removed
+// by RemoveReporter later.
+func AddReporter(reporter Reporter) {
+	if reporter == nil {
+		return
+	}
+
+	reportersMux.Lock()
+	reporters = append(reporters, reporter)
+	reportersMux.Unlock()
+}
+
+// RemoveReporter unregisters a reporter added by AddReporter. It ignores reporters
+// of uncomparable types, such as funcs, maps and slices, which cannot be told apart.
+func RemoveReporter(reporter Reporter) {
+	if reporter == nil || !reflect.TypeOf(reporter).Comparable() {
+		return
+	}
+
+	reportersMux.Lock()
+	defer reportersMux.Unlock()
+
+	for i, r := range reporters {
+		if r == reporter {
+			reporters = append(reporters[:i], reporters[i+1:]...)
+			return
+		}
+	}
+}
+
+// JSONReporter emits each failure as a JSON record, either as a line of the writer
+// or through t.Log with JSONReportPrefix envelope.
+type JSONReporter struct {
+	mux sync.Mutex
+	w   io.Writer
+}
+
+// NewJSONReporter returns a JSONReporter writing JSON lines to w.
+func NewJSONReporter(w io.Writer) *JSONReporter {
+	return &JSONReporter{
+		w: w,
+	}
+}
+
+// NewJSONFileReporter returns a JSONReporter appending JSON lines to the file of path.
+func NewJSONFileReporter(path string) (*JSONReporter, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewJSONReporter(file), nil
+}
+
+// NewJSONLogReporter returns a JSONReporter logging records through t.Log.
+//
+// NOTE: records are written to os.Stderr if t does not implement Log(args ...interface{}).
+func NewJSONLogReporter() *JSONReporter {
+	return &JSONReporter{}
+}
+
+// Report implements Reporter.
+func (reporter *JSONReporter) Report(t TestingT, record *FailureRecord) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[WARN] gospec: cannot marshal failure record: %v\n", err)
+		return
+	}
+
+	if reporter.w != nil {
+		reporter.mux.Lock()
+		reporter.w.Write(append(data, '\n'))
+		reporter.mux.Unlock()
+
+		return
+	}
+
+	if logger, ok := underlyingT(t).(LogT); ok {
+		logger.Log(JSONReportPrefix + string(data))
+
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, JSONReportPrefix+string(data))
+}
+
+// GitHubReporter emits each failure as an `::error` workflow command of GitHub Actions,
+// which is shown as an inline annotation at the assertion call of the test.
+type GitHubReporter struct {
+	mux sync.Mutex
+	w   io.Writer
+}
+
+// NewGitHubReporter returns a GitHubReporter writing workflow commands to w.
+//
+// NOTE: os.Stdout is used if w is nil.
+func NewGitHubReporter(w io.Writer) *GitHubReporter {
+	if w == nil {
+		w = os.Stdout
+	}
+
+	return &GitHubReporter{
+		w: w,
+	}
+}
+
+// Report implements Reporter.
+func (reporter *GitHubReporter) Report(t TestingT, record *FailureRecord) {
+	title := record.Error
+	if record.Assertion != "" {
+		title = record.Assertion + ": " + title
+	}
+	if record.Test != "" {
+		title = record.Test + ": " + title
+	}
+
+	lines := []string{}
+	if record.Message != "" {
+		lines = append(lines, record.Message)
+	}
+	for _, label := range record.Labels {
+		lines = append(lines, label.Label+": "+label.Content)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, record.Error)
+	}
+
+	command := "::error"
+	if n := len(record.Frames); n > 0 {
+		// the outermost frame is the call of assertion in test
+		frame := record.Frames[n-1]
+
+		command += fmt.Sprintf(" file=%s,line=%d,", escapeGitHubProperty(frame.File), frame.Line)
+	} else {
+		command += " "
+	}
+	command += "title=" + escapeGitHubProperty(title)
+	command += "::" + escapeGitHubData(strings.Join(lines, "\n"))
+
+	reporter.mux.Lock()
+	fmt.Fprintln(reporter.w, command)
+	reporter.mux.Unlock()
+}
+
+// escapeGitHubData escapes the data of a workflow command.
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeGitHubProperty escapes a property value of a workflow command.
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// newFailureRecord builds a FailureRecord from the labeled output of Errorf.
+func newFailureRecord(t TestingT, err string, frames []TraceFrame, output *testingOutput) *FailureRecord {
+	record := &FailureRecord{
+		Time:      time.Now(),
+		Assertion: getAssertionName(),
+		Error:     err,
+	}
+
+	for _, frame := range frames {
+		record.Trace = append(record.Trace, formatFrame(frame))
+
+		frame.File = getRelativePath(frame.File)
+		record.Frames = append(record.Frames, frame)
+	}
+
+	record.Test = nameOf(t)
+
+	for _, label := range output.labels {
+		switch label.label {
+		case labelErrorTrace, labelError:
+			continue
+
+		case labelMessages:
+			if label.content != "" {
+				record.Message = label.content
+			}
+			continue
+		}
+
+		name := strings.ToLower(strings.Trim(label.label, "+-: "))
+		switch name {
+		case "expected":
+			record.Expected = label.content
+
+		case "received", "actual":
+			record.Actual = label.content
+
+		case "diff":
+			record.Diff = label.content
+
+		}
+
+		record.Labels = append(record.Labels, FailureLabel{
+			Label:   label.label,
+			Content: label.content,
+		})
+	}
+
+	return record
+}
+
+// report dispatches the failure to all registered reporters, or t only if it's a Reporter, e.g. RecordingT,
+// since failures recorded by t are not failures of tests.
+func report(t TestingT, err string, frames []TraceFrame, output *testingOutput) {
+	if reporter, ok := underlyingT(t).(Reporter); ok {
+		reporter.Report(t, newFailureRecord(t, err, frames, output))
+		return
+	}
+
+	reportersMux.RLock()
+	targets := make([]Reporter, len(reporters))
+	copy(targets, reporters)
+	reportersMux.RUnlock()
+
+	if len(targets) == 0 {
+		return
+	}
+
+	record := newFailureRecord(t, err, frames, output)
+	for _, reporter := range targets {
+		reporter.Report(t, record)
+	}
+}
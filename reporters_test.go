@@ -0,0 +1,154 @@
+package gospec
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type testingLogger struct {
+	logs []string
+}
+
+func (tl *testingLogger) Errorf(format string, args ...interface{}) {}
+
+func (tl *testingLogger) Log(args ...interface{}) {
+	for _, arg := range args {
+		tl.logs = append(tl.logs, arg.(string))
+	}
+}
+
+func (tl *testingLogger) Name() string {
+	return "TestLogger"
+}
+
+func TestJSONReporter(t *testing.T) {
+	mockT := new(testing.T)
+
+	buf := bytes.NewBuffer(nil)
+
+	reporter := NewJSONReporter(buf)
+	AddReporter(reporter)
+
+	n := 1
+
+	Equal(mockT, "Hello", "World", "Hello, %s", "gospec")
+	NotEqual(mockT, n, 1)
+
+	RemoveReporter(reporter)
+
+	Equal(mockT, "Hello", "World")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !Len(t, lines, 2) {
+		return
+	}
+
+	var record FailureRecord
+	if NotError(t, json.Unmarshal([]byte(lines[0]), &record)) {
+		Equal(t, "Equal", record.Assertion)
+		Equal(t, "Expect to be equal", record.Error)
+		Equal(t, "Hello, gospec", record.Message)
+		Contains(t, record.Diff, "--- Expected")
+		NotEmpty(t, record.Trace)
+	}
+
+	record = FailureRecord{}
+	if NotError(t, json.Unmarshal([]byte(lines[1]), &record)) {
+		Equal(t, "NotEqual", record.Assertion)
+		Equal(t, "1", record.Expected)
+		Equal(t, "1", record.Actual)
+		Len(t, record.Labels, 3)
+		Equal(t, labelExpression, record.Labels[2].Label)
+		Equal(t, "NotEqual(mockT, n, 1)\nn → 1", record.Labels[2].Content)
+	}
+}
+
+func TestJSONLogReporter(t *testing.T) {
+	mockT := &testingLogger{}
+
+	reporter := NewJSONLogReporter()
+	AddReporter(reporter)
+
+	True(mockT, false)
+
+	RemoveReporter(reporter)
+
+	if !Len(t, mockT.logs, 1) {
+		return
+	}
+
+	True(t, strings.HasPrefix(mockT.logs[0], JSONReportPrefix))
+
+	var record FailureRecord
+	if NotError(t, json.Unmarshal([]byte(strings.TrimPrefix(mockT.logs[0], JSONReportPrefix)), &record)) {
+		Equal(t, "TestLogger", record.Test)
+		Equal(t, "True", record.Assertion)
+		Equal(t, "true", record.Expected)
+		Equal(t, "false", record.Actual)
+	}
+}
+
+func TestGitHubReporter(t *testing.T) {
+	mockT := &testingLogger{}
+
+	buf := bytes.NewBuffer(nil)
+
+	reporter := NewGitHubReporter(buf)
+	AddReporter(reporter)
+
+	Equal(mockT, 1, 2, "numbers, %s", "100%")
+
+	RemoveReporter(reporter)
+
+	command := buf.String()
+	True(t, strings.HasPrefix(command, "::error file=reporters_test.go,line="), command)
+	Contains(t, command, ",title=TestLogger%3A Equal%3A Expect to be equal::numbers, 100%25%0ADiff: --- int(1)%0A+++ int(2)")
+	True(t, strings.HasSuffix(command, "\n"))
+	Equal(t, 1, strings.Count(command, "\n"))
+}
+
+func Test_escapeGitHubProperty(t *testing.T) {
+	Equal(t, "a%3Ab%2Cc%25d%0Ae%0D", escapeGitHubProperty("a:b,c%d\ne\r"))
+	Equal(t, "a:b,c%25d%0Ae%0D", escapeGitHubData("a:b,c%d\ne\r"))
+}
+
+type reporterFunc func(t TestingT, record *FailureRecord)
+
+func (fn reporterFunc) Report(t TestingT, record *FailureRecord) {
+	fn(t, record)
+}
+
+func TestRemoveReporterWithUncomparableType(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+
+	reporter := NewJSONReporter(buf)
+	AddReporter(reporter)
+
+	var n int
+
+	fn := reporterFunc(func(t TestingT, record *FailureRecord) {
+		n++
+	})
+	AddReporter(fn)
+
+	NotPanics(t, func() {
+		RemoveReporter(fn)
+		RemoveReporter(reporter)
+	})
+
+	reportersMux.Lock()
+	for i, r := range reporters {
+		if _, ok := r.(reporterFunc); ok {
+			reporters = append(reporters[:i], reporters[i+1:]...)
+			break
+		}
+	}
+	reportersMux.Unlock()
+
+	True(new(testing.T), false)
+
+	Equal(t, 0, n)
+	Empty(t, buf.String())
+}
@@ -0,0 +1,523 @@
+package gospec
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Sampler generates a sample from r, which is seeded by Seed of the config.
+type Sampler func(r *rand.Rand) float64
+
+// BucketSampler generates a sample as the index of a bucket from r, which is seeded by Seed of the config.
+type BucketSampler func(r *rand.Rand) int
+
+// newRand returns a rand seeded by Seed of the config, or by current time if the seed is zero.
+// The seed is always reported in failure output for reproducing.
+func (c *Config) newRand() (*rand.Rand, int64) {
+	seed := c.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	return rand.New(rand.NewSource(seed)), seed
+}
+
+// sampleStats holds statistics of samples.
+type sampleStats struct {
+	n      int
+	mean   float64
+	stddev float64
+}
+
+// collectSamples runs sampler n times and returns samples with their statistics.
+func collectSamples(r *rand.Rand, n int, sampler Sampler) ([]float64, sampleStats) {
+	samples := make([]float64, n)
+
+	// Welford's online algorithm for numerical stability
+	var mean, m2 float64
+	for i := 0; i < n; i++ {
+		x := sampler(r)
+		samples[i] = x
+
+		delta := x - mean
+		mean += delta / float64(i+1)
+		m2 += delta * (x - mean)
+	}
+
+	stats := sampleStats{
+		n:    n,
+		mean: mean,
+	}
+	if n > 1 {
+		stats.stddev = math.Sqrt(m2 / float64(n-1))
+	}
+
+	return samples, stats
+}
+
+func (stats sampleStats) labels(seed int64) []labeledOutput {
+	return []labeledOutput{
+		{
+			label:   "Seed",
+			content: fmt.Sprintf("%d", seed),
+		},
+		{
+			label:   "Samples",
+			content: fmt.Sprintf("%d", stats.n),
+		},
+		{
+			label:   "Mean",
+			content: fmt.Sprintf("%g", stats.mean),
+		},
+		{
+			label:   "StdDev",
+			content: fmt.Sprintf("%g", stats.stddev),
+		},
+	}
+}
+
+// assertSamples checks n is positive before sampling.
+func assertSamples(t TestingT, n int, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	if n > 0 {
+		return true
+	}
+
+	return Errorf(t, "Number of samples must be positive", []labeledOutput{
+		{
+			label:   labelMessages,
+			content: formatExtras(extras...),
+		},
+		{
+			label:   "+received",
+			content: fmt.Sprintf("%d", n),
+		},
+	}, arguments{
+		"n": n,
+	})
+}
+
+// assertDelta asserts that delta is neither NaN nor negative, which never bounds any values.
+func assertDelta(t TestingT, delta float64, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	if !math.IsNaN(delta) && delta >= 0 {
+		return true
+	}
+
+	return Errorf(t, "Delta must NOT be NaN or negative", []labeledOutput{
+		{
+			label:   labelMessages,
+			content: formatExtras(extras...),
+		},
+		{
+			label:   "+received",
+			content: fmt.Sprintf("%g", delta),
+		},
+	}, arguments{
+		"delta": delta,
+	})
+}
+
+// assertAlpha asserts that alpha is a significance level within (0, 1), otherwise p-values are never
+// less than it, e.g. NaN, so any samples would fit.
+func assertAlpha(t TestingT, alpha float64, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	if alpha > 0 && alpha < 1 {
+		return true
+	}
+
+	return Errorf(t, "Alpha must be within (0, 1)", []labeledOutput{
+		{
+			label:   labelMessages,
+			content: formatExtras(extras...),
+		},
+		{
+			label:   "+received",
+			content: fmt.Sprintf("%g", alpha),
+		},
+	}, arguments{
+		"alpha": alpha,
+	})
+}
+
+// MeanInDelta asserts that the mean of n samples generated by sampler is within delta of mean.
+//
+//	assert.MeanInDelta(t, 10000, func(r *rand.Rand) float64 {
+//		return jitter(r, time.Second).Seconds()
+//	}, 1, 0.05)
+//
+// Returns whether the assertion was successful (true) or not (false).
+func MeanInDelta(t TestingT, n int, sampler Sampler, mean, delta float64, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	if !assertSamples(t, n, extras...) || !assertDelta(t, delta, extras...) {
+		return false
+	}
+
+	r, seed := configOf(t).newRand()
+
+	_, stats := collectSamples(r, n, sampler)
+	if math.IsNaN(stats.mean) || math.Abs(stats.mean-mean) > delta {
+		return Errorf(t, fmt.Sprintf("Expect the mean of samples within %v of %v", delta, mean), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-expected",
+				content: fmt.Sprintf("%g ± %g", mean, delta),
+			},
+			{
+				label:   "+received",
+				content: fmt.Sprintf("%g", stats.mean),
+			},
+		}, stats.labels(seed), arguments{
+			"n":     n,
+			"mean":  mean,
+			"delta": delta,
+		})
+	}
+
+	return true
+}
+
+// StdDevInDelta asserts that the sample standard deviation of n samples generated by sampler
+// is within delta of stddev.
+//
+// Returns whether the assertion was successful (true) or not (false).
+func StdDevInDelta(t TestingT, n int, sampler Sampler, stddev, delta float64, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	if !assertSamples(t, n, extras...) || !assertDelta(t, delta, extras...) {
+		return false
+	}
+
+	r, seed := configOf(t).newRand()
+
+	_, stats := collectSamples(r, n, sampler)
+	if math.IsNaN(stats.stddev) || math.Abs(stats.stddev-stddev) > delta {
+		return Errorf(t, fmt.Sprintf("Expect the standard deviation of samples within %v of %v", delta, stddev), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-expected",
+				content: fmt.Sprintf("%g ± %g", stddev, delta),
+			},
+			{
+				label:   "+received",
+				content: fmt.Sprintf("%g", stats.stddev),
+			},
+		}, stats.labels(seed), arguments{
+			"n":      n,
+			"stddev": stddev,
+			"delta":  delta,
+		})
+	}
+
+	return true
+}
+
+// ChiSquareFit asserts that the distribution of n samples generated by sampler into buckets fits
+// weights of buckets by Pearson's chi-square test, which fails if the p-value is less than alpha.
+//
+//	assert.ChiSquareFit(t, 10000, func(r *rand.Rand) int {
+//		return balancer.Pick(r)
+//	}, []float64{1, 1, 2}, 0.001)
+//
+// Returns whether the assertion was successful (true) or not (false).
+func ChiSquareFit(t TestingT, n int, sampler BucketSampler, weights []float64, alpha float64, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	if !assertSamples(t, n, extras...) || !assertAlpha(t, alpha, extras...) {
+		return false
+	}
+
+	total := 0.0
+	for _, weight := range weights {
+		if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			total = 0
+			break
+		}
+
+		total += weight
+	}
+	if len(weights) < 2 || total <= 0 {
+		return Errorf(t, "Weights must be at least 2 non-negative numbers with a positive sum", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "+received",
+				content: fmt.Sprintf("%v", weights),
+			},
+		}, arguments{
+			"n":       n,
+			"weights": weights,
+			"alpha":   alpha,
+		})
+	}
+
+	r, seed := configOf(t).newRand()
+
+	observed := make([]int, len(weights))
+	outliers := 0
+	for i := 0; i < n; i++ {
+		bucket := sampler(r)
+		if bucket < 0 || bucket >= len(weights) || weights[bucket] == 0 {
+			outliers++
+			continue
+		}
+
+		observed[bucket]++
+	}
+
+	expected := make([]float64, len(weights))
+	chi2 := 0.0
+	df := -1
+	for i, weight := range weights {
+		if weight == 0 {
+			continue
+		}
+
+		expected[i] = float64(n) * weight / total
+		chi2 += math.Pow(float64(observed[i])-expected[i], 2) / expected[i]
+		df++
+	}
+
+	pvalue := 0.0
+	if outliers == 0 && df > 0 {
+		pvalue = gammaQ(float64(df)/2, chi2/2)
+	} else if outliers == 0 {
+		pvalue = 1
+	}
+
+	if pvalue < alpha {
+		expects := make([]string, len(expected))
+		for i, e := range expected {
+			expects[i] = fmt.Sprintf("%.1f", e)
+		}
+
+		return Errorf(t, fmt.Sprintf("Expect samples fit weights by chi-square test with p-value >= %v", alpha), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "-expected",
+				content: "[" + strings.Join(expects, " ") + "]",
+			},
+			{
+				label:   "+received",
+				content: fmt.Sprintf("%v", observed),
+			},
+			{
+				label:   "Seed",
+				content: fmt.Sprintf("%d", seed),
+			},
+			{
+				label:   "Samples",
+				content: fmt.Sprintf("%d", n),
+			},
+			{
+				label:   "Out of Buckets",
+				content: fmt.Sprintf("%d", outliers),
+			},
+			{
+				label:   "Chi-Square",
+				content: fmt.Sprintf("%g (df=%d)", chi2, df),
+			},
+			{
+				label:   "P-Value",
+				content: fmt.Sprintf("%g", pvalue),
+			},
+		}, arguments{
+			"n":       n,
+			"weights": weights,
+			"alpha":   alpha,
+		})
+	}
+
+	return true
+}
+
+// KolmogorovSmirnovFit asserts that n samples generated by sampler fit the cumulative distribution
+// function cdf by one-sample Kolmogorov–Smirnov test, which fails if the p-value is less than alpha.
+//
+//	assert.KolmogorovSmirnovFit(t, 1000, func(r *rand.Rand) float64 {
+//		return r.Float64()
+//	}, func(x float64) float64 {
+//		return math.Max(0, math.Min(1, x))
+//	}, 0.001)
+//
+// Returns whether the assertion was successful (true) or not (false).
+func KolmogorovSmirnovFit(t TestingT, n int, sampler Sampler, cdf func(x float64) float64, alpha float64, extras ...interface{}) bool {
+	if h := helperOf(t); h != nil {
+		h.Helper()
+	}
+
+	if !assertSamples(t, n, extras...) || !assertAlpha(t, alpha, extras...) {
+		return false
+	}
+
+	if cdf == nil {
+		return Errorf(t, "CDF must NOT be nil", []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+		}, arguments{
+			"n":     n,
+			"alpha": alpha,
+		})
+	}
+
+	r, seed := configOf(t).newRand()
+
+	samples, stats := collectSamples(r, n, sampler)
+	sort.Float64s(samples)
+
+	d, at := 0.0, math.NaN()
+	for i, x := range samples {
+		f := cdf(x)
+		if math.IsNaN(f) {
+			d, at = math.NaN(), x
+			break
+		}
+
+		if lower := f - float64(i)/float64(n); lower > d {
+			d, at = lower, x
+		}
+		if upper := float64(i+1)/float64(n) - f; upper > d {
+			d, at = upper, x
+		}
+	}
+
+	pvalue := 0.0
+	if !math.IsNaN(d) {
+		sqrtn := math.Sqrt(float64(n))
+
+		pvalue = kolmogorovQ((sqrtn + 0.12 + 0.11/sqrtn) * d)
+	}
+
+	if pvalue < alpha {
+		return Errorf(t, fmt.Sprintf("Expect samples fit CDF by Kolmogorov-Smirnov test with p-value >= %v", alpha), []labeledOutput{
+			{
+				label:   labelMessages,
+				content: formatExtras(extras...),
+			},
+			{
+				label:   "Max Distance",
+				content: fmt.Sprintf("%g at x=%g", d, at),
+			},
+			{
+				label:   "P-Value",
+				content: fmt.Sprintf("%g", pvalue),
+			},
+		}, stats.labels(seed), arguments{
+			"n":     n,
+			"alpha": alpha,
+		})
+	}
+
+	return true
+}
+
+// gammaQ returns the regularized upper incomplete gamma function Q(a, x), which is the p-value of
+// chi-square statistic 2x with 2a degrees of freedom.
+func gammaQ(a, x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+
+	lgamma, _ := math.Lgamma(a)
+
+	if x < a+1 {
+		// series representation of P(a, x)
+		sum, term := 1/a, 1/a
+		for n := 1; n < 1000; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+
+			if math.Abs(term) < math.Abs(sum)*1e-15 {
+				break
+			}
+		}
+
+		return 1 - sum*math.Exp(-x+a*math.Log(x)-lgamma)
+	}
+
+	// continued fraction representation of Q(a, x) by modified Lentz's method
+	const tiny = 1e-300
+
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1; i < 1000; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+
+		d = 1 / d
+		delta := d * c
+		h *= delta
+
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+
+	return math.Exp(-x+a*math.Log(x)-lgamma) * h
+}
+
+// kolmogorovQ returns the complementary cumulative Kolmogorov distribution Q(lambda), which is
+// the asymptotic p-value of Kolmogorov–Smirnov statistic.
+func kolmogorovQ(lambda float64) float64 {
+	if lambda < 0.2 {
+		return 1
+	}
+
+	sum, sign := 0.0, 1.0
+	for j := 1; j <= 100; j++ {
+		term := 2 * sign * math.Exp(-2*float64(j*j)*lambda*lambda)
+		sum += term
+
+		if math.Abs(term) < 1e-12 {
+			break
+		}
+
+		sign = -sign
+	}
+
+	return math.Max(0, math.Min(1, sum))
+}
@@ -0,0 +1,170 @@
+package gospec
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// newSeededT returns a TestingT with the seed, which makes statistical assertions deterministic.
+func newSeededT(seed int64) TestingT {
+	return WithConfig(new(testing.T), func(c *Config) {
+		c.Seed = seed
+	})
+}
+
+func TestMeanInDelta(t *testing.T) {
+	mockT := newSeededT(1)
+
+	uniform := func(r *rand.Rand) float64 {
+		return r.Float64()
+	}
+
+	True(t, MeanInDelta(mockT, 10000, uniform, 0.5, 0.02))
+	False(t, MeanInDelta(mockT, 10000, uniform, 0.6, 0.02))
+	False(t, MeanInDelta(mockT, 0, uniform, 0.5, 0.02))
+
+	True(t, StdDevInDelta(mockT, 10000, uniform, math.Sqrt(1.0/12), 0.01))
+	False(t, StdDevInDelta(mockT, 10000, uniform, 1, 0.01))
+
+	// invalid deltas are rejected before sampling
+	rt := new(RecordingT)
+
+	sampled := false
+	never := func(r *rand.Rand) float64 {
+		sampled = true
+		return math.NaN()
+	}
+
+	False(t, MeanInDelta(rt, 10000, never, 0.5, math.NaN()))
+	False(t, MeanInDelta(rt, 10000, never, 0.5, -1))
+	False(t, StdDevInDelta(rt, 10000, never, 0.5, math.NaN()))
+	False(t, StdDevInDelta(rt, 10000, never, 0.5, -1))
+	False(t, sampled)
+
+	records := rt.Records()
+	if Len(t, records, 4) {
+		for _, record := range records {
+			Equal(t, "Delta must NOT be NaN or negative", record.Error)
+		}
+	}
+}
+
+func TestConfig_Seed(t *testing.T) {
+	rt := new(RecordingT)
+	seededT := WithConfig(rt, func(c *Config) {
+		c.Seed = 42
+	})
+
+	var first, second float64
+	MeanInDelta(seededT, 1, func(r *rand.Rand) float64 {
+		first = r.Float64()
+		return first
+	}, 0, 0)
+	MeanInDelta(seededT, 1, func(r *rand.Rand) float64 {
+		second = r.Float64()
+		return second
+	}, 0, 0)
+
+	Equal(t, first, second)
+
+	records := rt.Records()
+	if Len(t, records, 2) {
+		Equal(t, "42", testingLabelOf(records[0], "Seed"))
+	}
+}
+
+func TestChiSquareFit(t *testing.T) {
+	mockT := newSeededT(1)
+
+	weights := []float64{1, 1, 2}
+	weighted := func(r *rand.Rand) int {
+		switch n := r.Intn(4); n {
+		case 3:
+			return 2
+
+		default:
+			return n
+		}
+	}
+	biased := func(r *rand.Rand) int {
+		return r.Intn(3)
+	}
+
+	True(t, ChiSquareFit(mockT, 10000, weighted, weights, 0.001))
+	False(t, ChiSquareFit(mockT, 10000, biased, weights, 0.001))
+	False(t, ChiSquareFit(mockT, 100, func(r *rand.Rand) int {
+		return 3
+	}, weights, 0.001))
+	False(t, ChiSquareFit(mockT, 100, weighted, []float64{1}, 0.001))
+	False(t, ChiSquareFit(mockT, 100, weighted, []float64{1, -1}, 0.001))
+
+	// zero weight buckets are never expected
+	True(t, ChiSquareFit(mockT, 10000, weighted, []float64{1, 1, 2, 0}, 0.001))
+
+	// invalid alphas are rejected before sampling
+	rt := new(RecordingT)
+
+	False(t, ChiSquareFit(rt, 100, biased, weights, math.NaN()))
+	False(t, ChiSquareFit(rt, 100, biased, weights, 0))
+	False(t, ChiSquareFit(rt, 100, biased, weights, 1))
+
+	records := rt.Records()
+	if Len(t, records, 3) {
+		for _, record := range records {
+			Equal(t, "Alpha must be within (0, 1)", record.Error)
+		}
+	}
+}
+
+func TestKolmogorovSmirnovFit(t *testing.T) {
+	mockT := newSeededT(1)
+
+	uniform := func(r *rand.Rand) float64 {
+		return r.Float64()
+	}
+	uniformCDF := func(x float64) float64 {
+		return math.Max(0, math.Min(1, x))
+	}
+	normalCDF := func(x float64) float64 {
+		return 0.5 * math.Erfc(-x/math.Sqrt2)
+	}
+
+	True(t, KolmogorovSmirnovFit(mockT, 1000, uniform, uniformCDF, 0.001))
+	True(t, KolmogorovSmirnovFit(mockT, 1000, func(r *rand.Rand) float64 {
+		return r.NormFloat64()
+	}, normalCDF, 0.001))
+	False(t, KolmogorovSmirnovFit(mockT, 1000, uniform, func(x float64) float64 {
+		return uniformCDF(x) * uniformCDF(x)
+	}, 0.001))
+	False(t, KolmogorovSmirnovFit(mockT, 1000, uniform, func(x float64) float64 {
+		return math.NaN()
+	}, 0.001))
+
+	// invalid alphas and nil CDFs are rejected before sampling
+	rt := new(RecordingT)
+
+	False(t, KolmogorovSmirnovFit(rt, 1000, uniform, uniformCDF, math.NaN()))
+	False(t, KolmogorovSmirnovFit(rt, 1000, uniform, uniformCDF, -0.5))
+	False(t, KolmogorovSmirnovFit(rt, 1000, uniform, nil, 0.001))
+
+	records := rt.Records()
+	if Len(t, records, 3) {
+		Equal(t, "Alpha must be within (0, 1)", records[0].Error)
+		Equal(t, "Alpha must be within (0, 1)", records[1].Error)
+		Equal(t, "CDF must NOT be nil", records[2].Error)
+	}
+}
+
+func Test_gammaQ(t *testing.T) {
+	// Q(1, x) = exp(-x)
+	InDelta(t, math.Exp(-0.5), gammaQ(1, 0.5), 1e-12)
+	InDelta(t, math.Exp(-5), gammaQ(1, 5), 1e-12)
+
+	// p-value of chi-square 3.841 with 1 degree of freedom is 0.05
+	InDelta(t, 0.05, gammaQ(0.5, 3.841/2), 1e-4)
+	Equal(t, 1.0, gammaQ(1, 0))
+
+	// p-value of Kolmogorov-Smirnov lambda 1.36 is 0.05
+	InDelta(t, 0.05, kolmogorovQ(1.358), 1e-3)
+}
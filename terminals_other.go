@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !freebsd
+// +build !linux,!darwin,!freebsd
+
+package gospec
+
+// terminalWidth returns false on platforms without the TIOCGWINSZ ioctl, which fall back to $COLUMNS.
+func terminalWidth(fd uintptr) (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,29 @@
+//go:build linux || darwin || freebsd
+// +build linux darwin freebsd
+
+package gospec
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// winsize is the struct of window sizes filled by the TIOCGWINSZ ioctl.
+type winsize struct {
+	Row    uint16
+	Col    uint16
+	Xpixel uint16
+	Ypixel uint16
+}
+
+// terminalWidth returns columns of the terminal of fd, and false if fd is not a terminal.
+func terminalWidth(fd uintptr) (int, bool) {
+	ws := new(winsize)
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(ws)))
+	if errno != 0 || ws.Col == 0 {
+		return 0, false
+	}
+
+	return int(ws.Col), true
+}
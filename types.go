@@ -1,10 +1,13 @@
 package gospec
 
+import "context"
+
 const (
 	labelNewLine    = "\n\r\t"
 	labelErrorTrace = "Error Trace"
 	labelError      = "Error"
 	labelMessages   = "Message"
+	labelDiff       = "Diff"
 )
 
 type (
@@ -17,6 +20,54 @@ type (
 	}
 )
 
+// Optional capabilities of TestingT, which are implemented by *testing.T, *testing.B and testing.TB.
+// Assertions detect them and fall back gracefully for minimal Errorf-only implementations.
+type (
+	// HelperT marks the calling func as a test helper, so failures are reported at the caller.
+	HelperT interface {
+		Helper()
+	}
+
+	// FailNowT stops the test after failures if the config of assertions enables FailNow, see Require.
+	FailNowT interface {
+		FailNow()
+	}
+
+	// LogT receives failure records of JSONLogReporter.
+	LogT interface {
+		Log(args ...interface{})
+	}
+
+	// NameT names the test of failure records.
+	NameT interface {
+		Name() string
+	}
+
+	// CleanupT removes temp data of assertions, e.g. data spooled by ReaderContains, after the test.
+	CleanupT interface {
+		Cleanup(func())
+	}
+
+	// ContextT cancels long-running assertions, e.g. comparing huge streams, once the test is done.
+	ContextT interface {
+		Context() context.Context
+	}
+
+	// TempDirT provides the dir of temp files of assertions, which is removed after the test.
+	TempDirT interface {
+		TempDir() string
+	}
+)
+
+type (
+	// TraceFrame represents a stack frame leading from the test to the failed assertion.
+	TraceFrame struct {
+		File     string `json:"file"`
+		Line     int    `json:"line"`
+		Function string `json:"function,omitempty"`
+	}
+)
+
 type (
 	// Comparison defines a custom func that returns true on success and false on failure
 	Comparison func() (success bool)